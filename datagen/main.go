@@ -19,7 +19,7 @@ const (
 
 type emojiPart struct {
 	name         string
-	version      float32
+	version      tr51.Version
 	modifierBase bool
 	presentation bool
 	profession   bool
@@ -44,28 +44,30 @@ func main() {
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			log.Fatalf("could not read: %v", err)
+			log.Fatalf("could not read emoji-data.txt: %v", err)
 		}
 
-		isEmoji := l.HasProperty("Emoji")
-		isPresentation := l.HasProperty("Emoji_Presentation")
-		isModifierBase := l.HasProperty("Emoji_Modifier_Base")
+		isEmoji := l.HasProperty(tr51.PropertyEmoji)
+		isPresentation := l.HasProperty(tr51.PropertyEmojiPresentation)
+		isModifierBase := l.HasProperty(tr51.PropertyEmojiModifierBase)
 
 		if !(isEmoji || isPresentation || isModifierBase) {
 			continue
 		}
 
+		version := l.EffectiveVersion()
+
 		low, high := l.AsRange()
 		for r := low; r <= high; r++ {
 			ep := emojiParts[r]
 
-			if ep.version != l.Version {
-				if !(ep.version == 0.0 && isEmoji) {
+			if ep.version != version {
+				if !(ep.version == (tr51.Version{}) && isEmoji) {
 					// got inconsistent version
-					log.Printf("%c: prop=%+v version=%v was=%v", r, l.Properties, l.Version, ep)
+					log.Printf("emoji-data.txt:%d: %c: prop=%+v version=%v was=%v", l.LineNumber, r, l.Properties, version, ep)
 				}
 			}
-			ep.version = l.Version
+			ep.version = version
 			ep.presentation = ep.presentation || isPresentation
 			ep.modifierBase = ep.modifierBase || isModifierBase
 
@@ -87,10 +89,10 @@ func main() {
 	}
 
 	// helper to process single
-	processTestSingle := func(l tr51.Line) {
-		if l.HasProperty("component") && l.Single != 0 {
+	processTestSingle := func(src string, l tr51.Line) {
+		if l.HasProperty(tr51.PropertyComponent) && l.Single != 0 {
 			// ok, we'll just name it
-		} else if !l.HasProperty("fully-qualified") {
+		} else if !l.HasProperty(tr51.PropertyFullyQualified) {
 			return
 		}
 
@@ -98,10 +100,10 @@ func main() {
 		qualified := l.AsSequence()
 		raw := []rune(tr51.Unqualify(string(qualified)))
 		if len(raw) == 0 {
-			log.Fatalf("unqualified emoji is empty: %v", l.AsString())
+			log.Fatalf("%s:%d: unqualified emoji is empty: %v", src, l.LineNumber, l.AsString())
 		}
 
-		version := int(l.Version)
+		version := l.EmojiVersion.Major
 
 		r := raw[0]
 		var name string
@@ -183,9 +185,9 @@ func main() {
 			if err == io.EOF {
 				break
 			} else if err != nil {
-				log.Fatalf("could not read: %v", err)
+				log.Fatalf("could not read %s: %v", src, err)
 			}
-			processTestSingle(l)
+			processTestSingle(src, l)
 		}
 	}
 
@@ -1,48 +1,42 @@
-// Package main will print all emoji from the latest revision, except unqualified emoji.
+// Package main will print all emoji from a recent revision, except unqualified emoji.
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
-	"net/http"
 
 	"github.com/samthor/tr51"
 	"github.com/samthor/tr51/emoji"
+	"github.com/samthor/tr51/fetcher"
 )
 
-const (
-	dataDataURL       = "https://unicode.org/Public/emoji/latest/emoji-data.txt"
-	testDataURL       = "https://unicode.org/Public/emoji/latest/emoji-test.txt"
-	qualifiedProperty = "fully-qualified"
-)
+// version is the Emoji version to print, rather than "latest", which changes without notice.
+var version = tr51.Version{Major: 15, Minor: 1}
 
-func readURL(url string) *bytes.Buffer {
-	resp, err := http.Get(url)
-	if err != nil {
-		log.Fatal(err)
-	}
+var flagUnpinned = flag.Bool("unpinned", false, "allow files without a pinned SHA-256 sum")
 
-	all, err := ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
+func open(f *fetcher.Fetcher, name string) *tr51.Reader {
+	r, err := f.Open(context.Background(), version, name)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	return bytes.NewBuffer(all)
+	return r
 }
 
 func main() {
-	data, err := emoji.NewData(tr51.NewReader(readURL(dataDataURL)))
+	flag.Parse()
+	f := &fetcher.Fetcher{AllowUnpinned: *flagUnpinned}
+	data, err := emoji.NewData(open(f, "emoji-data.txt"))
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	payload := make(map[string]string)
-	r := tr51.NewReader(readURL(testDataURL))
+	r := open(f, "emoji-test.txt")
 	for {
 		out, err := r.Read()
 		if err == io.EOF {
@@ -51,12 +45,12 @@ func main() {
 			log.Fatal(err)
 		}
 
-		if !out.HasEmoji() || !out.HasProperty(qualifiedProperty) {
+		if !out.HasEmoji() || !out.HasProperty(tr51.PropertyFullyQualified) {
 			continue
 		}
 
 		s := string(out.AsSequence())
-		norm := data.Normalize(s)
+		norm := data.Strip(s)
 		if _, ok := payload[norm]; ok || len(norm) == 0 {
 			continue // dup re: gender or tone
 		}
@@ -12,8 +12,13 @@ type Cats struct {
 	vendors []string
 }
 
-// NewCats returns a new Cats struct.
+// NewCats returns a new Cats struct. Returns ErrWrongKind if passed any other known kind of data
+// than the custom format or emoji-data.txt.
 func NewCats(r *tr51.Reader) (*Cats, error) {
+	if err := checkKind(r, tr51.KindCats, tr51.KindData); err != nil {
+		return nil, err
+	}
+
 	im := &Cats{
 		emoji: make(map[string][]string),
 	}
@@ -27,9 +32,12 @@ func NewCats(r *tr51.Reader) (*Cats, error) {
 			return nil, err
 		}
 
-		if !l.HasEmoji() && l.Notes != "" {
-			activeTitle = l.Notes
-			im.vendors = append(im.vendors, activeTitle)
+		if l.Kind != tr51.LineData {
+			// any comment, including headers and groups, is a title for the following emoji
+			if l.Notes != "" {
+				activeTitle = l.Notes
+				im.vendors = append(im.vendors, activeTitle)
+			}
 			continue
 		}
 
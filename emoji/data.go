@@ -2,27 +2,34 @@ package emoji
 
 import (
 	"io"
+	"slices"
 
 	"github.com/samthor/tr51"
 )
 
 type emojiData struct {
-	unqualified  bool    // whether this needs VS16
-	modifierBase bool    // whether this can be modified
-	version      float32 // unicode version from
+	unqualified  bool         // whether this needs VS16
+	modifierBase bool         // whether this can be modified
+	version      tr51.Version // see tr51.Line.EffectiveVersion
 }
 
 // Data wraps parsed data from emoji-data.txt.
 type Data struct {
 	emoji       map[rune]emojiData
 	unqualified int
+	sets        map[tr51.Property]*tr51.RuneSet
 }
 
 // NewData returns a new Data struct, which helps validate raw emoji parts. Expects emoji-data.txt
-// from Emoji 2.0+.
+// from Emoji 2.0+, and returns ErrWrongKind if passed any other known kind of data.
 func NewData(r *tr51.Reader) (*Data, error) {
+	if err := checkKind(r, tr51.KindData); err != nil {
+		return nil, err
+	}
+
 	m := make(map[rune]emojiData)
 	var unqualified int
+	sets := make(map[tr51.Property]*tr51.RuneSet)
 
 	for {
 		l, err := r.Read()
@@ -32,15 +39,26 @@ func NewData(r *tr51.Reader) (*Data, error) {
 			return nil, err
 		}
 
-		isEmoji := l.HasProperty("Emoji")
-		isPresentation := l.HasProperty("Emoji_Presentation")
+		for _, p := range l.Properties {
+			set, ok := sets[tr51.Property(p)]
+			if !ok {
+				set = &tr51.RuneSet{}
+				sets[tr51.Property(p)] = set
+			}
+			set.AddLine(l)
+		}
+
+		isEmoji := l.HasProperty(tr51.PropertyEmoji)
+		isPresentation := l.HasProperty(tr51.PropertyEmojiPresentation)
+
+		version := l.EffectiveVersion()
 
 		low, high := l.AsRange()
 		if isEmoji || isPresentation {
 			for r := low; r <= high; r++ {
 				v := m[r]
 				v.unqualified = isEmoji
-				v.version = l.Version
+				v.version = version
 				m[r] = v
 
 				if isEmoji {
@@ -51,7 +69,7 @@ func NewData(r *tr51.Reader) (*Data, error) {
 			}
 		}
 
-		if l.HasProperty("Emoji_Modifier_Base") {
+		if l.HasProperty(tr51.PropertyEmojiModifierBase) {
 			for r := low; r <= high; r++ {
 				v := m[r]
 				v.modifierBase = true
@@ -60,13 +78,49 @@ func NewData(r *tr51.Reader) (*Data, error) {
 		}
 	}
 
-	return &Data{m, unqualified}, nil
+	return &Data{emoji: m, unqualified: unqualified, sets: sets}, nil
+}
+
+// Set returns the runes with the passed property, e.g., tr51.PropertyEmojiPresentation. This is
+// empty if the property was not found.
+func (ed *Data) Set(p tr51.Property) tr51.RuneSet {
+	if set, ok := ed.sets[p]; ok {
+		return set.Clone()
+	}
+	return tr51.RuneSet{}
+}
+
+// Properties returns all properties found in the data, sorted.
+func (ed *Data) Properties() []tr51.Property {
+	out := make([]tr51.Property, 0, len(ed.sets))
+	for p := range ed.sets {
+		out = append(out, p)
+	}
+	slices.Sort(out)
+	return out
 }
 
+// Presentation controls the variation selector Normalize adds after standalone emoji, i.e., those
+// not part of a ZWJ, modifier or keycap sequence.
+type Presentation int
+
+const (
+	PresentationDefault Presentation = iota // add VS16 to emoji without default emoji presentation
+	PresentationText                        // add VS15 to request text presentation, where supported
+	PresentationEmoji                       // add VS16 to request emoji presentation, where supported
+	PresentationKeep                        // keep VS15 or VS16 only if present in the input
+)
+
 // StripOpts controls what Normalize will strip.
 type StripOpts struct {
 	Tone   bool
 	Gender bool
+
+	Presentation Presentation
+
+	// Variations, if set, is used to find which emoji support text or emoji presentation. Otherwise,
+	// only emoji without default emoji presentation are assumed to support both.
+	Variations *Variations
 }
 
 var (
@@ -108,10 +162,17 @@ func (ed *Data) Normalize(raw string, opts StripOpts) string {
 		return raw
 	}
 
-	// #1: Remove VS16 and other modifiers.
+	// #1: Remove VS15, VS16 and other modifiers.
+	var selectors map[int]rune
 	for _, r := range raw {
-		if r == runeVS16 {
-			// remove VS16
+		if r == runeVS15 || r == runeVS16 {
+			// remove, but record the selector following each rune if we're keeping them
+			if opts.Presentation == PresentationKeep {
+				if selectors == nil {
+					selectors = make(map[int]rune)
+				}
+				selectors[len(pending)-1] = r
+			}
 			continue
 		} else if IsSkinTone(r) {
 			if opts.Tone {
@@ -207,8 +268,17 @@ func (ed *Data) Normalize(raw string, opts StripOpts) string {
 			}
 
 			out = append(out, r)
+			next := pending[i+1]
+			standalone := pendingZWJ != i && next != runeZWJ && next != runeCap && !IsSkinTone(next)
+			if standalone && opts.Presentation != PresentationDefault {
+				if vs := opts.selector(r, d, selectors[i]); vs != 0 {
+					out = append(out, vs)
+				}
+				allowZWJ = i + 1
+				continue
+			}
 			if d.unqualified {
-				if IsSkinTone(pending[i+1]) {
+				if IsSkinTone(next) {
 					// do nothing as this acts as a VS16
 					continue
 				}
@@ -223,3 +293,26 @@ func (ed *Data) Normalize(raw string, opts StripOpts) string {
 	// #3: Profit!
 	return string(out)
 }
+
+// selector returns the variation selector to follow a standalone emoji, or zero for none. The
+// input selector is the one following the emoji in the raw string, if any.
+func (opts StripOpts) selector(r rune, d emojiData, input rune) rune {
+	hasText, hasEmoji := d.unqualified, d.unqualified
+	if opts.Variations != nil {
+		hasText, hasEmoji = opts.Variations.HasText(r), opts.Variations.HasEmoji(r)
+	}
+
+	switch opts.Presentation {
+	case PresentationText:
+		if hasText {
+			return runeVS15
+		}
+	case PresentationEmoji:
+		if hasEmoji || d.unqualified {
+			return runeVS16
+		}
+	case PresentationKeep:
+		return input
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package emoji
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/samthor/tr51"
@@ -50,6 +51,15 @@ func TestData(t *testing.T) {
 		{o, "👨🏼‍🚒", "👨‍🚒"},
 		{o, "🕵🏾‍♂", "🕵️"},
 		{none, "🕵🏾‍♂", "🕵🏾‍♂️"},
+
+		// presentation
+		{StripOpts{Presentation: PresentationText}, "♾️", "♾\ufe0e"},
+		{StripOpts{Presentation: PresentationText}, "🚀", "🚀"},
+		{StripOpts{Presentation: PresentationText}, "🕵🏾", "🕵🏾"},
+		{StripOpts{Presentation: PresentationEmoji}, "♾\ufe0e", "♾️"},
+		{StripOpts{Presentation: PresentationEmoji}, "🚀", "🚀"},
+		{StripOpts{Presentation: PresentationKeep}, "♾\ufe0e♾♾️", "♾\ufe0e♾♾️"},
+		{StripOpts{Presentation: PresentationKeep}, "🕵‍♂", "🕵️‍♂️"},
 	}
 	for _, td := range data {
 		actual := ed.Normalize(td.in, td.opts)
@@ -59,3 +69,36 @@ func TestData(t *testing.T) {
 	}
 
 }
+
+func TestDataSet(t *testing.T) {
+	raw := `
+231A..231B    ; Emoji                #  1.1  [2] (⌚..⌛)    watch..hourglass done
+267E..267F    ; Emoji                #  4.1  [2] (♾️..♿)    infinity..wheelchair symbol
+231A..231B    ; Emoji_Presentation   #  1.1  [2] (⌚..⌛)    watch..hourglass done
+267F          ; Emoji_Presentation   #  4.1  [1] (♿)       wheelchair symbol
+`
+
+	ed, err := NewData(tr51.NewReader(bytes.NewBufferString(raw)))
+	if err != nil {
+		t.Fatalf("couldn't NewData: %v", err)
+	}
+
+	expected := []tr51.Property{tr51.PropertyEmoji, tr51.PropertyEmojiPresentation}
+	if actual := ed.Properties(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, was %v", expected, actual)
+	}
+
+	emoji := ed.Set(tr51.PropertyEmoji)
+	if emoji.Len() != 4 || !emoji.Contains(0x267e) {
+		t.Errorf("expected Emoji to contain 4 runes, was %v", emoji.Ranges())
+	}
+
+	text := emoji.Difference(ed.Set(tr51.PropertyEmojiPresentation))
+	if expected := []tr51.RuneRange{{Low: 0x267e, High: 0x267e}}; !reflect.DeepEqual(text.Ranges(), expected) {
+		t.Errorf("expected %v, was %v", expected, text.Ranges())
+	}
+
+	if ed.Set(tr51.PropertyEmojiModifier).Len() != 0 {
+		t.Errorf("expected missing property to be empty")
+	}
+}
@@ -0,0 +1,132 @@
+package emoji
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/samthor/tr51"
+)
+
+// TestChange describes an emoji which changed between two versions of emoji-test.txt.
+type TestChange struct {
+	Old TestLine `json:"old"`
+	New TestLine `json:"new"`
+}
+
+// TestDiff describes the changes between two versions of emoji-test.txt.
+type TestDiff struct {
+	Added   []TestLine   `json:"added,omitempty"`
+	Removed []TestLine   `json:"removed,omitempty"`
+	Renamed []TestChange `json:"renamed,omitempty"` // notes changed
+	Moved   []TestChange `json:"moved,omitempty"`   // group or subgroup changed
+	Status  []TestChange `json:"status,omitempty"`  // qualification status changed, added or removed
+}
+
+// Empty returns whether there are no changes.
+func (d *TestDiff) Empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Renamed)+len(d.Moved)+len(d.Status) == 0
+}
+
+// Diff returns the changes from this Test to a newer one. Emoji are matched regardless of VS16,
+// and are reported in the order of the newer Test, except for removed emoji. Changes to status
+// are reported for each variant of an emoji found in both, where an added or removed variant has
+// an empty status on one side.
+func (t *Test) Diff(newer *Test) *TestDiff {
+	d := &TestDiff{}
+
+	t.TestEach(func(each *TestEach) {
+		key := tr51.Unqualify(each.Emoji)
+		if _, ok := newer.emoji[key]; !ok {
+			d.Removed = append(d.Removed, t.emoji[key].variants[0])
+		}
+	})
+
+	newer.TestEach(func(each *TestEach) {
+		key := tr51.Unqualify(each.Emoji)
+		n := newer.emoji[key]
+		o, ok := t.emoji[key]
+		if !ok {
+			d.Added = append(d.Added, n.variants[0])
+			return
+		}
+
+		change := TestChange{Old: o.variants[0], New: n.variants[0]}
+		if change.Old.Notes != change.New.Notes {
+			d.Renamed = append(d.Renamed, change)
+		}
+		if change.Old.Group != change.New.Group || change.Old.Subgroup != change.New.Subgroup {
+			d.Moved = append(d.Moved, change)
+		}
+		d.Status = append(d.Status, diffStatus(o.variants, n.variants)...)
+	})
+
+	return d
+}
+
+// diffStatus returns status changes between the variants of a single emoji.
+func diffStatus(older, newer []TestLine) []TestChange {
+	var out []TestChange
+
+	find := func(all []TestLine, emoji string) (TestLine, bool) {
+		for _, line := range all {
+			if line.Emoji == emoji {
+				return line, true
+			}
+		}
+		return TestLine{Emoji: emoji}, false
+	}
+
+	for _, n := range newer {
+		o, _ := find(older, n.Emoji)
+		if o.Status != n.Status {
+			out = append(out, TestChange{Old: o, New: n})
+		}
+	}
+	for _, o := range older {
+		if n, ok := find(newer, o.Emoji); !ok {
+			out = append(out, TestChange{Old: o, New: n})
+		}
+	}
+	return out
+}
+
+// WriteText writes a human-readable description of the changes, one per line.
+func (d *TestDiff) WriteText(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	for _, line := range d.Added {
+		printf("added: %s %s (%s)\n", line.Emoji, line.Notes, location(line))
+	}
+	for _, line := range d.Removed {
+		printf("removed: %s %s (%s)\n", line.Emoji, line.Notes, location(line))
+	}
+	for _, c := range d.Renamed {
+		printf("renamed: %s %q -> %q\n", c.New.Emoji, c.Old.Notes, c.New.Notes)
+	}
+	for _, c := range d.Moved {
+		printf("moved: %s %s -> %s\n", c.New.Emoji, location(c.Old), location(c.New))
+	}
+	for _, c := range d.Status {
+		printf("status: %s %s -> %s\n", c.New.Emoji, statusOrNone(c.Old), statusOrNone(c.New))
+	}
+	return err
+}
+
+func location(line TestLine) string {
+	if line.Subgroup == "" {
+		return line.Group
+	}
+	return line.Group + " / " + line.Subgroup
+}
+
+func statusOrNone(line TestLine) string {
+	if line.Status == "" {
+		return "none"
+	}
+	return string(line.Status)
+}
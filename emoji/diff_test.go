@@ -0,0 +1,103 @@
+package emoji
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestTestDiff(t *testing.T) {
+	older := `
+# group: Smileys & Emotion
+# subgroup: face-smiling
+1F600                                      ; fully-qualified     # 😀 E1.0 grinning face
+263A FE0F                                  ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                       ; unqualified         # ☺ E0.6 smiling face
+# subgroup: face-affection
+1F970                                      ; fully-qualified     # 🥰 E11.0 smiling face with 3 hearts
+1F929                                      ; fully-qualified     # 🤩 E5.0 star-struck
+# group: Activities
+# subgroup: event
+1F383                                      ; fully-qualified     # 🎃 E0.6 jack-o-lantern
+`
+	newer := `
+# group: Smileys & Emotion
+# subgroup: face-smiling
+1F600                                      ; fully-qualified     # 😀 E1.0 grinning face
+263A FE0F                                  ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                       ; minimally-qualified # ☺ E0.6 smiling face
+1F929                                      ; fully-qualified     # 🤩 E5.0 star-struck
+# subgroup: face-affection
+1F970                                      ; fully-qualified     # 🥰 E11.0 smiling face with hearts
+1FAE8                                      ; fully-qualified     # 🫨 E15.0 shaking face
+# subgroup: face-costume
+1F383                                      ; fully-qualified     # 🎃 E0.6 jack-o-lantern
+`
+
+	reader := func(raw string) *tr51.Reader {
+		return tr51.NewReader(bytes.NewBufferString(raw))
+	}
+	o, err := NewTest(reader(older))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+	n, err := NewTest(reader(newer))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+
+	if d := o.Diff(o); !d.Empty() {
+		t.Errorf("expected no changes, was %+v", d)
+	}
+
+	d := o.Diff(n)
+	smileys := "Smileys & Emotion"
+	fq := tr51.PropertyFullyQualified
+	e06 := tr51.Version{Major: 0, Minor: 6}
+	e5 := tr51.Version{Major: 5, Minor: 0}
+	e11 := tr51.Version{Major: 11, Minor: 0}
+	e15 := tr51.Version{Major: 15, Minor: 0}
+	expected := &TestDiff{
+		Added: []TestLine{
+			{Emoji: "🫨", Notes: "shaking face", Group: smileys, Subgroup: "face-affection", Status: fq, Version: e15},
+		},
+		Renamed: []TestChange{{
+			Old: TestLine{Emoji: "🥰", Notes: "smiling face with 3 hearts", Group: smileys, Subgroup: "face-affection", Status: fq, Version: e11},
+			New: TestLine{Emoji: "🥰", Notes: "smiling face with hearts", Group: smileys, Subgroup: "face-affection", Status: fq, Version: e11},
+		}},
+		Moved: []TestChange{{
+			Old: TestLine{Emoji: "🤩", Notes: "star-struck", Group: smileys, Subgroup: "face-affection", Status: fq, Version: e5},
+			New: TestLine{Emoji: "🤩", Notes: "star-struck", Group: smileys, Subgroup: "face-smiling", Status: fq, Version: e5},
+		}, {
+			Old: TestLine{Emoji: "🎃", Notes: "jack-o-lantern", Group: "Activities", Subgroup: "event", Status: fq, Version: e06},
+			New: TestLine{Emoji: "🎃", Notes: "jack-o-lantern", Group: smileys, Subgroup: "face-costume", Status: fq, Version: e06},
+		}},
+		Status: []TestChange{{
+			Old: TestLine{Emoji: "☺", Notes: "smiling face", Group: smileys, Subgroup: "face-smiling", Status: tr51.PropertyUnqualified, Version: e06},
+			New: TestLine{Emoji: "☺", Notes: "smiling face", Group: smileys, Subgroup: "face-smiling", Status: tr51.PropertyMinimallyQualified, Version: e06},
+		}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Errorf("expected %+v, was %+v", expected, d)
+	}
+
+	var b bytes.Buffer
+	if err := d.WriteText(&b); err != nil {
+		t.Fatalf("couldn't WriteText: %v", err)
+	}
+	text := `added: 🫨 shaking face (Smileys & Emotion / face-affection)
+renamed: 🥰 "smiling face with 3 hearts" -> "smiling face with hearts"
+moved: 🤩 Smileys & Emotion / face-affection -> Smileys & Emotion / face-smiling
+moved: 🎃 Activities / event -> Smileys & Emotion / face-costume
+status: ☺ unqualified -> minimally-qualified
+`
+	if b.String() != text {
+		t.Errorf("expected %q, was %q", text, b.String())
+	}
+
+	if d := n.Diff(o); len(d.Removed) != 1 || d.Removed[0].Emoji != "🫨" {
+		t.Errorf("expected shaking face to be removed, was %+v", d.Removed)
+	}
+}
@@ -1,3 +1,4 @@
-// Package emoji provides some higher-level abstractions over the emoji-data.txt and emoji-test.txt
-// TR51 data files.
+// Package emoji provides some higher-level abstractions over the emoji-data.txt, emoji-test.txt,
+// emoji-sequences.txt, emoji-zwj-sequences.txt, emoji-variation-sequences.txt and
+// emoji-ordering.txt TR51 data files.
 package emoji
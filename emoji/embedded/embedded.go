@@ -0,0 +1,93 @@
+// Package embedded provides TR51 data compiled into the binary, for hermetic builds and offline
+// tests. Importing it adds the embedded files to the binary, so the rest of this module stays
+// data-free.
+//
+// Only files found in the data directory are embedded. Use the regen command to replace them with
+// a different Emoji version. Files without a pin in fetcher.DefaultPins need its -unpinned flag,
+// and regen logs their SHA-256 sums so they can be pinned.
+package embedded
+
+//go:generate go run ./regen -version 15.1
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/samthor/tr51"
+	"github.com/samthor/tr51/emoji"
+)
+
+//go:embed data/*.txt
+var files embed.FS
+
+// ErrMissing is returned when a TR51 file was not embedded.
+var ErrMissing = errors.New("TR51 file not embedded")
+
+// Files returns the embedded TR51 files, e.g., "emoji-test.txt".
+func Files() fs.FS {
+	sub, _ := fs.Sub(files, "data")
+	return sub
+}
+
+// Open returns a Reader for an embedded TR51 file, e.g., "emoji-test.txt".
+func Open(name string) (*tr51.Reader, error) {
+	raw, err := fs.ReadFile(Files(), name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %v", ErrMissing, name)
+	} else if err != nil {
+		return nil, err
+	}
+	return tr51.NewReader(bytes.NewReader(raw)), nil
+}
+
+var (
+	loadData = sync.OnceValues(func() (*emoji.Data, error) {
+		r, err := Open("emoji-data.txt")
+		if err != nil {
+			return nil, err
+		}
+		return emoji.NewData(r)
+	})
+
+	loadTest = sync.OnceValues(func() (*emoji.Test, error) {
+		r, err := Open("emoji-test.txt")
+		if err != nil {
+			return nil, err
+		}
+		return emoji.NewTest(r)
+	})
+
+	loadSequences = sync.OnceValues(func() (*emoji.Sequences, error) {
+		var readers []*tr51.Reader
+		for _, name := range []string{"emoji-sequences.txt", "emoji-zwj-sequences.txt"} {
+			r, err := Open(name)
+			if err != nil {
+				return nil, err
+			}
+			readers = append(readers, r)
+		}
+		return emoji.NewSequences(readers...)
+	})
+)
+
+// Data returns the parsed embedded emoji-data.txt. It is parsed on first use, and the same value
+// is returned to all callers.
+func Data() (*emoji.Data, error) {
+	return loadData()
+}
+
+// Test returns the parsed embedded emoji-test.txt. It is parsed on first use, and the same value
+// is returned to all callers.
+func Test() (*emoji.Test, error) {
+	return loadTest()
+}
+
+// Sequences returns the parsed embedded emoji-sequences.txt and emoji-zwj-sequences.txt. They are
+// parsed on first use, and the same value is returned to all callers.
+func Sequences() (*emoji.Sequences, error) {
+	return loadSequences()
+}
@@ -0,0 +1,119 @@
+package embedded
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+
+	"github.com/samthor/tr51"
+	"github.com/samthor/tr51/emoji"
+)
+
+func TestEmbedded(t *testing.T) {
+	r, err := Open("emoji-test.txt")
+	if err != nil {
+		t.Fatalf("couldn't Open: %v", err)
+	}
+	h, err := r.Header()
+	if err != nil {
+		t.Fatalf("couldn't read header: %v", err)
+	}
+	if expected, _ := tr51.ParseVersion(Version); h.Version != expected {
+		t.Errorf("expected version %v, was %v", expected, h.Version)
+	}
+
+	if _, err := Open("emoji-nothing.txt"); !errors.Is(err, ErrMissing) {
+		t.Errorf("expected ErrMissing, was %v", err)
+	}
+}
+
+func TestEmbeddedTest(t *testing.T) {
+	var wg sync.WaitGroup
+	all := make([]*emoji.Test, 8)
+	for i := range all {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			et, err := Test()
+			if err != nil {
+				t.Errorf("couldn't load Test: %v", err)
+			}
+			all[i] = et
+		}()
+	}
+	wg.Wait()
+
+	for _, et := range all[1:] {
+		if et != all[0] {
+			t.Errorf("expected the same Test for all callers")
+		}
+	}
+	if name := all[0].Name("😀"); name != "grinning face" {
+		t.Errorf("expected grinning face, was %q", name)
+	}
+	if err := all[0].Verify(); err != nil {
+		t.Errorf("expected embedded emoji-test.txt to match its counts, was %v", err)
+	}
+}
+
+// skipMissing checks that err is ErrMissing and skips the test if any passed file isn't embedded.
+func skipMissing(t *testing.T, err error, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, statErr := fs.Stat(Files(), name); statErr != nil {
+			if !errors.Is(err, ErrMissing) {
+				t.Errorf("expected ErrMissing, was %v", err)
+			}
+			t.Skipf("%v is not embedded, run go generate", name)
+		}
+	}
+}
+
+func TestEmbeddedData(t *testing.T) {
+	data, err := Data()
+	skipMissing(t, err, "emoji-data.txt")
+	if err != nil {
+		t.Fatalf("couldn't load Data: %v", err)
+	}
+	if again, _ := Data(); again != data {
+		t.Errorf("expected the same Data for all callers")
+	}
+
+	if !data.Set(tr51.PropertyEmojiPresentation).Contains('😀') {
+		t.Errorf("expected 😀 to have Emoji_Presentation")
+	}
+	if actual := data.Strip("👍🏽"); actual != "👍" {
+		t.Errorf("expected 👍, was %q", actual)
+	}
+}
+
+func TestEmbeddedSequences(t *testing.T) {
+	seq, err := Sequences()
+	skipMissing(t, err, "emoji-sequences.txt", "emoji-zwj-sequences.txt")
+	if err != nil {
+		t.Fatalf("couldn't load Sequences: %v", err)
+	}
+	if again, _ := Sequences(); again != seq {
+		t.Errorf("expected the same Sequences for all callers")
+	}
+
+	type testData struct {
+		emoji string
+		typ   tr51.Property
+	}
+	data := []testData{
+		{"🇦🇺", tr51.PropertyRGIEmojiFlagSequence},
+		{"👍🏽", tr51.PropertyRGIEmojiModifierSequence},
+		{"🏳️‍🌈", tr51.PropertyRGIEmojiZWJSequence},
+		{"🐦‍🔥", tr51.PropertyRGIEmojiZWJSequence}, // new in 15.1
+	}
+	for _, td := range data {
+		actual, ok := seq.Lookup(td.emoji)
+		if !ok {
+			t.Errorf("expected %v to be found", td.emoji)
+		} else if actual.Type != td.typ {
+			t.Errorf("expected %v, was %v", td.typ, actual.Type)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package main of regen downloads TR51 files for an Emoji version into the embedded package.
+//
+// Run via "go generate" in the embedded package, after changing the version there.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/samthor/tr51"
+	"github.com/samthor/tr51/emoji"
+	"github.com/samthor/tr51/fetcher"
+)
+
+var (
+	flagVersion  = flag.String("version", "", "Emoji version to download, e.g., 15.1")
+	flagDir      = flag.String("dir", ".", "directory of the embedded package")
+	flagUnpinned = flag.Bool("unpinned", false, "allow files without a pinned SHA-256 sum, e.g., for a new version")
+)
+
+var files = []string{
+	"emoji-data.txt",
+	"emoji-test.txt",
+	"emoji-sequences.txt",
+	"emoji-zwj-sequences.txt",
+}
+
+func main() {
+	flag.Parse()
+	v, err := tr51.ParseVersion(*flagVersion)
+	if err != nil {
+		log.Fatalf("bad -version: %v", err)
+	}
+
+	f := &fetcher.Fetcher{AllowUnpinned: *flagUnpinned}
+	for _, name := range files {
+		raw, err := f.Fetch(context.Background(), v, name)
+		if err != nil {
+			log.Fatalf("could not fetch %v: %v", name, err)
+		}
+		if _, ok := fetcher.DefaultPins[fetcher.Pin{Version: v, Name: name}]; !ok {
+			// so it can be added to DefaultPins after review
+			log.Printf("unpinned %v: sha256 %x", name, sha256.Sum256(raw))
+		}
+		if name == "emoji-test.txt" {
+			// new versions aren't pinned, so check the file against its own summaries
+			test, err := emoji.NewTest(tr51.NewReader(bytes.NewReader(raw)))
+			if err == nil {
+				err = test.Verify()
+			}
+			if err != nil {
+				log.Fatalf("could not verify %v: %v", name, err)
+			}
+		}
+		target := filepath.Join(*flagDir, "data", name)
+		if err := os.WriteFile(target, raw, 0644); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("wrote %v", target)
+	}
+
+	source := fmt.Sprintf(`// Code generated by regen; DO NOT EDIT.
+
+package embedded
+
+// Version is the Emoji version of the embedded TR51 files.
+const Version = %q
+`, v.String())
+	if err := os.WriteFile(filepath.Join(*flagDir, "version.go"), []byte(source), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -3,6 +3,7 @@ package emoji
 const (
 	runeZWJ          = 0x200d
 	runeCap          = 0x20e3
+	runeVS15         = 0xfe0e
 	runeVS16         = 0xfe0f
 	runeTagSpace     = 0xe0020
 	runeTagCancel    = 0xe007f
@@ -0,0 +1,28 @@
+package emoji
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/samthor/tr51"
+)
+
+// ErrWrongKind is returned when a constructor is passed a different kind of TR51 data.
+var ErrWrongKind = errors.New("wrong kind of TR51 data")
+
+// checkKind returns an error if the passed reader contains a known kind of TR51 data which isn't
+// one of the allowed kinds.
+func checkKind(r *tr51.Reader, allowed ...tr51.Kind) error {
+	kind, err := r.Kind()
+	if err != nil {
+		return err
+	} else if kind == tr51.KindUnknown {
+		return nil
+	}
+	for _, k := range allowed {
+		if kind == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: got %v, expected %v", ErrWrongKind, kind, allowed[0])
+}
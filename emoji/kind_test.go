@@ -0,0 +1,38 @@
+package emoji
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestWrongKind(t *testing.T) {
+	data := "1F93C..1F93E  ; Emoji                #  9.0  [3] (🤼..🤾)    people wrestling..person playing handball\n"
+	test := "1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face\n"
+
+	reader := func(raw string) *tr51.Reader {
+		return tr51.NewReader(bytes.NewBufferString(raw))
+	}
+
+	if _, err := NewData(reader(test)); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("expected NewData to fail with test data, was %v", err)
+	}
+	if _, err := NewTest(reader(data)); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("expected NewTest to fail with emoji data, was %v", err)
+	}
+	if _, err := NewCats(reader(test)); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("expected NewCats to fail with test data, was %v", err)
+	}
+
+	if _, err := NewData(reader(data)); err != nil {
+		t.Errorf("expected NewData to succeed with emoji data, was %v", err)
+	}
+	if _, err := NewTest(reader(test)); err != nil {
+		t.Errorf("expected NewTest to succeed with test data, was %v", err)
+	}
+	if _, err := NewCats(reader(data)); err != nil {
+		t.Errorf("expected NewCats to succeed with emoji data, was %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package emoji
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/samthor/tr51"
+)
+
+// Unknown emoji sort after all known emoji, and variants not in the data sort right after their
+// base emoji.
+const (
+	orderUnknown = math.MaxUint32
+	orderExact   = 0
+	orderVariant = 1
+)
+
+// Reorder replaces the order used by Compare with the order of emoji in the passed data, normally
+// emoji-ordering.txt. Emoji which aren't listed sort as if they weren't in this Test, and listed
+// emoji which aren't in this Test are ignored. This isn't kept by Tables.
+func (t *Test) Reorder(r *tr51.Reader) error {
+	if err := checkKind(r, tr51.KindOrdering, tr51.KindTest); err != nil {
+		return err
+	}
+
+	order := make(map[string]int, len(t.emoji))
+	for {
+		l, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if l.Kind != tr51.LineData {
+			continue
+		}
+		key := tr51.Unqualify(string(l.AsSequence()))
+		if _, ok := order[key]; !ok {
+			order[key] = len(order)
+		}
+	}
+
+	for key, test := range t.emoji {
+		index, ok := order[key]
+		if !ok {
+			index = -1
+		}
+		test.order = index
+		t.emoji[key] = test
+	}
+	return nil
+}
+
+// position returns where the passed emoji sorts, before comparing the emoji itself.
+func (t *Test) position(s string) (uint32, byte) {
+	if test, ok := t.emoji[tr51.Unqualify(s)]; ok && test.order >= 0 {
+		return uint32(test.order), orderExact
+	}
+	if base := baseOf(s); base != s {
+		if test, ok := t.emoji[tr51.Unqualify(base)]; ok && test.order >= 0 {
+			return uint32(test.order), orderVariant
+		}
+	}
+	return orderUnknown, orderExact
+}
+
+// baseOf returns the passed emoji without skin tones and gender signs, e.g., "🏃🏽‍♀️" becomes "🏃".
+func baseOf(s string) string {
+	runes := []rune(s)
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if IsSkinTone(r) {
+			continue
+		} else if r == runeZWJ && i+1 < len(runes) && IsGender(runes[i+1]) {
+			i++ // skip gender, any VS16 is removed by Unqualify
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// Compare returns an integer comparing two emoji in the order of emoji-test.txt, or of
+// emoji-ordering.txt if passed to Reorder, for use with slices.SortFunc. Skin tone and gender
+// variants which aren't in the data sort right after their base emoji, and unknown emoji sort
+// last. Ties, such as between the qualified and unqualified forms of an emoji, are broken by
+// code point.
+func (t *Test) Compare(a, b string) int {
+	aOrder, aVariant := t.position(a)
+	bOrder, bVariant := t.position(b)
+	if aOrder != bOrder {
+		if aOrder < bOrder {
+			return -1
+		}
+		return +1
+	} else if aVariant != bVariant {
+		return int(aVariant) - int(bVariant)
+	}
+	return strings.Compare(a, b)
+}
+
+// SortKey returns a key for the passed emoji, such that comparing keys as bytes matches Compare.
+// This is useful for database indexes, but keys change along with the data.
+func (t *Test) SortKey(s string) []byte {
+	order, variant := t.position(s)
+	out := make([]byte, 0, 5+len(s))
+	out = binary.BigEndian.AppendUint32(out, order)
+	out = append(out, variant)
+	return append(out, s...)
+}
+
+// SortFunc sorts a slice in the order of Compare, where emoji returns the emoji of each element.
+func SortFunc[S ~[]E, E any](t *Test, x S, emoji func(E) string) {
+	slices.SortStableFunc(x, func(a, b E) int {
+		return t.Compare(emoji(a), emoji(b))
+	})
+}
@@ -0,0 +1,86 @@
+package emoji
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestTestCompare(t *testing.T) {
+	raw := `
+# group: Smileys & Emotion
+1F600                                      ; fully-qualified     # 😀 E1.0 grinning face
+263A FE0F                                  ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                       ; unqualified         # ☺ E0.6 smiling face
+# group: People & Body
+1F44B                                      ; fully-qualified     # 👋 E0.6 waving hand
+1F3C3                                      ; fully-qualified     # 🏃 E0.6 person running
+1F3C3 200D 2642 FE0F                       ; fully-qualified     # 🏃‍♂️ E4.0 man running
+1F91D                                      ; fully-qualified     # 🤝 E3.0 handshake
+`
+	et, err := NewTest(tr51.NewReader(bytes.NewBufferString(raw)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+
+	expected := []string{
+		"😀",
+		"☺", // tie with qualified form is broken by code point
+		"☺️",
+		"👋",
+		"👋🏻", // unknown variant follows its base
+		"👋🏿",
+		"🏃",
+		"🏃🏽‍♀️",
+		"🏃‍♂️",
+		"🤝",
+		"A", // unknown emoji sort last, by code point
+		"🫨",
+	}
+
+	for i := 0; i < 10; i++ {
+		actual := slices.Clone(expected)
+		if i != 0 {
+			slices.Reverse(actual)
+			actual[0], actual[i] = actual[i], actual[0]
+		}
+		slices.SortFunc(actual, et.Compare)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %v, was %v", expected, actual)
+		}
+	}
+
+	for i := 1; i < len(expected); i++ {
+		a, b := et.SortKey(expected[i-1]), et.SortKey(expected[i])
+		if bytes.Compare(a, b) != -1 {
+			t.Errorf("expected key of %s to sort before %s, was %x vs %x", expected[i-1], expected[i], a, b)
+		}
+	}
+
+	type reaction struct {
+		Emoji string
+		Count int
+	}
+	reactions := []reaction{{"🫨", 1}, {"🤝", 2}, {"😀", 3}}
+	SortFunc(et, reactions, func(r reaction) string { return r.Emoji })
+	if expected := []reaction{{"😀", 3}, {"🤝", 2}, {"🫨", 1}}; !reflect.DeepEqual(reactions, expected) {
+		t.Errorf("expected %v, was %v", expected, reactions)
+	}
+
+	ordering := `# emoji-ordering.txt
+U+1F91D ; 3.0 # 🤝 handshake
+U+1F600 ; 1.0 # 😀 grinning face
+U+1F3C3 ; 0.6 # 🏃 person running
+`
+	if err := et.Reorder(tr51.NewReader(bytes.NewBufferString(ordering))); err != nil {
+		t.Fatalf("couldn't Reorder: %v", err)
+	}
+	actual := []string{"☺️", "👋", "🏃‍♂️", "😀", "🏃", "🤝"}
+	slices.SortFunc(actual, et.Compare)
+	if expected := []string{"🤝", "😀", "🏃", "🏃‍♂️", "☺️", "👋"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, was %v", expected, actual)
+	}
+}
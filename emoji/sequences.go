@@ -0,0 +1,134 @@
+package emoji
+
+import (
+	"io"
+	"strings"
+
+	"github.com/samthor/tr51"
+)
+
+// sequenceTypes maps type fields from Emoji 2.0-12.0 to their current names.
+var sequenceTypes = map[tr51.Property]tr51.Property{
+	tr51.PropertyEmojiCombiningSequence: tr51.PropertyEmojiKeycapSequence,
+	tr51.PropertyEmojiFlagSequence:      tr51.PropertyRGIEmojiFlagSequence,
+	tr51.PropertyEmojiTagSequence:       tr51.PropertyRGIEmojiTagSequence,
+	tr51.PropertyEmojiModifierSequence:  tr51.PropertyRGIEmojiModifierSequence,
+	tr51.PropertyEmojiZWJSequence:       tr51.PropertyRGIEmojiZWJSequence,
+}
+
+// Sequence describes a single RGI emoji from emoji-sequences.txt or emoji-zwj-sequences.txt.
+type Sequence struct {
+	Emoji       string
+	Type        tr51.Property // type field, using current names e.g., RGI_Emoji_ZWJ_Sequence
+	Description string
+	Version     tr51.Version // see tr51.Line.EffectiveVersion
+}
+
+// Sequences wraps parsed data from emoji-sequences.txt and emoji-zwj-sequences.txt.
+type Sequences struct {
+	emoji map[string]int // unqualified emoji to index in all
+	all   []Sequence
+}
+
+// NewSequences returns a new Sequences struct, containing every sequence from all passed readers.
+// Expects emoji-sequences.txt or emoji-zwj-sequences.txt from Emoji 2.0+, and returns ErrWrongKind
+// if passed any other known kind of data. Type fields from older data are renamed to their
+// current names, e.g., Emoji_ZWJ_Sequence becomes RGI_Emoji_ZWJ_Sequence.
+func NewSequences(readers ...*tr51.Reader) (*Sequences, error) {
+	s := &Sequences{
+		emoji: make(map[string]int),
+	}
+
+	for _, r := range readers {
+		if err := checkKind(r, tr51.KindSequences, tr51.KindZWJSequences); err != nil {
+			return nil, err
+		}
+
+		for {
+			l, err := r.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+
+			if !l.HasEmoji() || len(l.Properties) == 0 {
+				continue
+			}
+			s.addLine(&l)
+		}
+	}
+
+	return s, nil
+}
+
+// addLine adds all emoji from the passed line, ignoring any seen before.
+func (s *Sequences) addLine(l *tr51.Line) {
+	typ := tr51.Property(l.Properties[0])
+	if renamed, ok := sequenceTypes[typ]; ok {
+		typ = renamed
+	}
+
+	var description string
+	if len(l.Properties) > 1 {
+		description = l.Properties[1]
+	}
+
+	version := l.EffectiveVersion()
+
+	add := func(emoji, description string) {
+		key := tr51.Unqualify(emoji)
+		if _, ok := s.emoji[key]; ok {
+			return
+		}
+		s.emoji[key] = len(s.all)
+		s.all = append(s.all, Sequence{
+			Emoji:       emoji,
+			Type:        typ,
+			Description: description,
+			Version:     version,
+		})
+	}
+
+	if l.Single != 0 || len(l.Sequence) != 0 {
+		add(string(l.AsSequence()), description)
+		return
+	}
+
+	// ranges only contain the first and last descriptions e.g., "watch..hourglass done"
+	low, high := l.AsRange()
+	descLow, descHigh, _ := strings.Cut(description, "..")
+	for r := low; r <= high; r++ {
+		switch r {
+		case low:
+			add(string(r), descLow)
+		case high:
+			add(string(r), descHigh)
+		default:
+			add(string(r), "")
+		}
+	}
+}
+
+// Lookup returns the Sequence for the passed emoji, which may be missing VS16.
+func (s *Sequences) Lookup(emoji string) (Sequence, bool) {
+	index, ok := s.emoji[tr51.Unqualify(emoji)]
+	if !ok {
+		return Sequence{}, false
+	}
+	return s.all[index], true
+}
+
+// Len returns the number of sequences found.
+func (s *Sequences) Len() int {
+	return len(s.all)
+}
+
+// Each enumerates through all found sequences, in the order they were read.
+func (s *Sequences) Each(fn func(*Sequence)) {
+	var each Sequence
+	for _, seq := range s.all {
+		each = seq
+		fn(&each)
+	}
+}
@@ -0,0 +1,66 @@
+package emoji
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestSequences(t *testing.T) {
+	sequences := `# emoji-sequences.txt
+231A..231B    ; Basic_Emoji                  ; watch..hourglass done                                          # E0.6   [2] (⌚..⌛)
+0023 FE0F 20E3; Emoji_Keycap_Sequence        ; keycap: \x{23}                                                 # E0.6   [1] (#️⃣)
+1F1E6 1F1E8   ; RGI_Emoji_Flag_Sequence      ; flag: Ascension Island                                         # E2.0   [1] (🇦🇨)
+`
+	zwj := `# emoji-zwj-sequences.txt
+1F468 200D 2695 FE0F                        ; Emoji_ZWJ_Sequence  ; man health worker                                              #  9.0  [1] (👨‍⚕️)
+`
+
+	s, err := NewSequences(
+		tr51.NewReader(bytes.NewBufferString(sequences)),
+		tr51.NewReader(bytes.NewBufferString(zwj)),
+	)
+	if err != nil {
+		t.Fatalf("couldn't NewSequences: %v", err)
+	}
+
+	if s.Len() != 5 {
+		t.Errorf("expected 5 sequences, was %d", s.Len())
+	}
+
+	expected := map[string]Sequence{
+		"⌚":   {"⌚", tr51.PropertyBasicEmoji, "watch", tr51.Version{Major: 0, Minor: 6}},
+		"⌛":   {"⌛", tr51.PropertyBasicEmoji, "hourglass done", tr51.Version{Major: 0, Minor: 6}},
+		"#⃣":  {"#️⃣", tr51.PropertyEmojiKeycapSequence, "keycap: #", tr51.Version{Major: 0, Minor: 6}},
+		"🇦🇨":  {"🇦🇨", tr51.PropertyRGIEmojiFlagSequence, "flag: Ascension Island", tr51.Version{Major: 2, Minor: 0}},
+		"👨‍⚕": {"👨‍⚕️", tr51.PropertyRGIEmojiZWJSequence, "man health worker", tr51.Version{Major: 9, Minor: 0}},
+	}
+	for emoji, expected := range expected {
+		actual, ok := s.Lookup(emoji)
+		if !ok {
+			t.Errorf("expected %q to be found", emoji)
+		} else if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %+v, was %+v", expected, actual)
+		}
+	}
+
+	if _, ok := s.Lookup("🤼"); ok {
+		t.Errorf("expected missing emoji to not be found")
+	}
+
+	var order []string
+	s.Each(func(seq *Sequence) {
+		order = append(order, seq.Emoji)
+	})
+	if expected := []string{"⌚", "⌛", "#️⃣", "🇦🇨", "👨‍⚕️"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, was %v", expected, order)
+	}
+
+	test := "1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face\n"
+	if _, err := NewSequences(tr51.NewReader(bytes.NewBufferString(test))); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("expected NewSequences to fail with test data, was %v", err)
+	}
+}
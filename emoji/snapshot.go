@@ -0,0 +1,446 @@
+package emoji
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"maps"
+	"slices"
+
+	"github.com/samthor/tr51"
+)
+
+// Snapshots start with a fixed header: magic, format version, kind and a CRC-32 of the payload.
+// The payload uses varints, and strings are written once and then referenced by index. It starts
+// with the number of unique strings, so readers can allocate once.
+const (
+	snapshotMagic      = "tr51"
+	snapshotVersion    = 1
+	snapshotHeaderSize = len(snapshotMagic) + 2 + 4
+)
+
+const (
+	snapshotData byte = iota + 1
+	snapshotTest
+	snapshotCats
+)
+
+// ErrSnapshot indicates a binary snapshot which is corrupt, or of a different kind or version.
+var ErrSnapshot = errors.New("invalid snapshot")
+
+type snapshotWriter struct {
+	buf     []byte
+	strings map[string]int
+}
+
+func newSnapshotWriter() *snapshotWriter {
+	return &snapshotWriter{strings: make(map[string]int)}
+}
+
+func (w *snapshotWriter) uint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *snapshotWriter) int(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+// string writes the index+1 of a string seen before, or zero followed by a new string.
+func (w *snapshotWriter) string(s string) {
+	if index, ok := w.strings[s]; ok {
+		w.uint(uint64(index) + 1)
+		return
+	}
+	w.strings[s] = len(w.strings)
+	w.uint(0)
+	w.uint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *snapshotWriter) version(v tr51.Version) {
+	w.uint(uint64(v.Major))
+	w.uint(uint64(v.Minor))
+}
+
+// writeCounts writes a map in key order, as its size plus one, or zero if nil.
+func writeCounts[K ~string](w *snapshotWriter, m map[K]int) {
+	if m == nil {
+		w.uint(0)
+		return
+	}
+	w.uint(uint64(len(m)) + 1)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		w.string(string(key))
+		w.int(int64(m[key]))
+	}
+}
+
+// finish returns the header followed by the payload.
+func (w *snapshotWriter) finish(kind byte) []byte {
+	payload := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(w.buf)), uint64(len(w.strings)))
+	payload = append(payload, w.buf...)
+
+	out := make([]byte, 0, snapshotHeaderSize+len(payload))
+	out = append(out, snapshotMagic...)
+	out = append(out, snapshotVersion, kind)
+	out = binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(payload))
+	return append(out, payload...)
+}
+
+type snapshotReader struct {
+	buf     []byte
+	all     string // payload as a string, so strings share one allocation
+	strings []string
+	err     error
+}
+
+// newSnapshotReader checks the header of a snapshot, and returns a reader for its payload.
+func newSnapshotReader(b []byte, kind byte) (*snapshotReader, error) {
+	if len(b) < snapshotHeaderSize || string(b[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("%w: bad header", ErrSnapshot)
+	}
+	b = b[len(snapshotMagic):]
+	if b[0] != snapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrSnapshot, b[0])
+	} else if b[1] != kind {
+		return nil, fmt.Errorf("%w: wrong kind %d, expected %d", ErrSnapshot, b[1], kind)
+	}
+	payload := b[6:]
+	if binary.BigEndian.Uint32(b[2:6]) != crc32.ChecksumIEEE(payload) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrSnapshot)
+	}
+	r := &snapshotReader{buf: payload, all: string(payload)}
+	r.strings = make([]string, 0, r.count())
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r, nil
+}
+
+func (r *snapshotReader) fail(what string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: bad %s", ErrSnapshot, what)
+	}
+	r.buf = nil
+}
+
+func (r *snapshotReader) uint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail("uvarint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *snapshotReader) int() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail("varint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// count reads a length, which can't be more than the remaining bytes.
+func (r *snapshotReader) count() int {
+	v := r.uint()
+	if v > uint64(len(r.buf)) {
+		r.fail("count")
+		return 0
+	}
+	return int(v)
+}
+
+func (r *snapshotReader) string() string {
+	index := r.uint()
+	if index != 0 {
+		if index > uint64(len(r.strings)) {
+			r.fail("string index")
+			return ""
+		}
+		return r.strings[index-1]
+	}
+	size := r.uint()
+	if size > uint64(len(r.buf)) {
+		r.fail("string")
+		return ""
+	}
+	start := len(r.all) - len(r.buf)
+	s := r.all[start : start+int(size)]
+	r.buf = r.buf[size:]
+	r.strings = append(r.strings, s)
+	return s
+}
+
+func (r *snapshotReader) version() tr51.Version {
+	return tr51.Version{Major: int(r.uint()), Minor: int(r.uint())}
+}
+
+// readCounts reads a map written by writeCounts.
+func readCounts[K ~string](r *snapshotReader) map[K]int {
+	count := r.count()
+	if count == 0 {
+		return nil
+	}
+	out := make(map[K]int, count-1)
+	for i := 1; i < count; i++ {
+		key := K(r.string())
+		out[key] = int(r.int())
+	}
+	return out
+}
+
+// done returns any error, including if there are trailing bytes.
+func (r *snapshotReader) done() error {
+	if r.err == nil && len(r.buf) != 0 {
+		r.fail("trailing data")
+	}
+	return r.err
+}
+
+// MarshalBinary returns a compact snapshot of this Data.
+func (ed *Data) MarshalBinary() ([]byte, error) {
+	w := newSnapshotWriter()
+
+	runes := make([]rune, 0, len(ed.emoji))
+	for r := range ed.emoji {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+
+	w.int(int64(ed.unqualified))
+	w.uint(uint64(len(runes)))
+	var prev rune
+	for _, r := range runes {
+		d := ed.emoji[r]
+		var flags uint64
+		if d.unqualified {
+			flags |= 1
+		}
+		if d.modifierBase {
+			flags |= 2
+		}
+		w.uint(uint64(r - prev))
+		w.uint(flags)
+		w.version(d.version)
+		prev = r
+	}
+
+	properties := ed.Properties()
+	w.uint(uint64(len(properties)))
+	for _, p := range properties {
+		ranges := ed.sets[p].Ranges()
+		w.string(string(p))
+		w.uint(uint64(len(ranges)))
+		for _, rr := range ranges {
+			w.int(int64(rr.Low))
+			w.uint(uint64(rr.High - rr.Low))
+		}
+	}
+
+	return w.finish(snapshotData), nil
+}
+
+// UnmarshalBinary replaces this Data with a snapshot from MarshalBinary.
+func (ed *Data) UnmarshalBinary(b []byte) error {
+	r, err := newSnapshotReader(b, snapshotData)
+	if err != nil {
+		return err
+	}
+
+	out := Data{unqualified: int(r.int())}
+	count := r.count()
+	out.emoji = make(map[rune]emojiData, count)
+	var prev rune
+	for i := 0; i < count; i++ {
+		prev += rune(r.uint())
+		flags := r.uint()
+		out.emoji[prev] = emojiData{
+			unqualified:  flags&1 != 0,
+			modifierBase: flags&2 != 0,
+			version:      r.version(),
+		}
+	}
+
+	count = r.count()
+	out.sets = make(map[tr51.Property]*tr51.RuneSet, count)
+	for i := 0; i < count; i++ {
+		set := &tr51.RuneSet{}
+		out.sets[tr51.Property(r.string())] = set
+		for j, ranges := 0, r.count(); j < ranges; j++ {
+			low := rune(r.int())
+			set.Add(low, low+rune(r.uint()))
+		}
+	}
+
+	if err := r.done(); err != nil {
+		return err
+	}
+	*ed = out
+	return nil
+}
+
+// MarshalBinary returns a compact snapshot of this Test.
+func (t *Test) MarshalBinary() ([]byte, error) {
+	w := newSnapshotWriter()
+
+	writeEmoji := func(key string) {
+		test := t.emoji[key]
+		w.string(key)
+		w.string(test.qualified)
+		w.string(test.notes)
+		w.string(test.subgroup)
+		w.int(int64(test.order))
+		w.uint(uint64(len(test.variants)))
+		for _, line := range test.variants {
+			w.string(line.Emoji)
+			w.string(string(line.Status))
+			w.string(line.Notes)
+			w.string(line.Group)
+			w.string(line.Subgroup)
+			w.version(line.Version)
+		}
+	}
+
+	seen := make(map[string]bool, len(t.emoji))
+	w.uint(uint64(len(t.emoji)))
+	w.uint(uint64(len(t.groups)))
+	for _, gi := range t.groups {
+		w.string(gi.name)
+		w.uint(uint64(len(gi.emoji)))
+		for _, key := range gi.emoji {
+			writeEmoji(key)
+			seen[key] = true
+		}
+	}
+
+	var ungrouped []string
+	for key := range t.emoji {
+		if !seen[key] {
+			ungrouped = append(ungrouped, key)
+		}
+	}
+	slices.Sort(ungrouped)
+	w.uint(uint64(len(ungrouped)))
+	for _, key := range ungrouped {
+		writeEmoji(key)
+	}
+
+	writeCounts(w, t.declared.Groups)
+	writeCounts(w, t.declared.GroupsWithoutModifiers)
+	writeCounts(w, t.declared.Status)
+
+	return w.finish(snapshotTest), nil
+}
+
+// UnmarshalBinary replaces this Test with a snapshot from MarshalBinary.
+func (t *Test) UnmarshalBinary(b []byte) error {
+	r, err := newSnapshotReader(b, snapshotTest)
+	if err != nil {
+		return err
+	}
+
+	out := Test{emoji: make(map[string]emojiTest, r.count())}
+	readEmoji := func() string {
+		key := r.string()
+		test := emojiTest{qualified: r.string(), notes: r.string(), subgroup: r.string(), order: int(r.int())}
+		test.variants = make([]TestLine, r.count())
+		for i := range test.variants {
+			test.variants[i] = TestLine{
+				Emoji:    r.string(),
+				Status:   tr51.Property(r.string()),
+				Notes:    r.string(),
+				Group:    r.string(),
+				Subgroup: r.string(),
+				Version:  r.version(),
+			}
+		}
+		out.emoji[key] = test
+		return key
+	}
+
+	out.groups = make([]*groupInfo, r.count())
+	for i := range out.groups {
+		gi := &groupInfo{name: r.string()}
+		for j, count := 0, r.count(); j < count; j++ {
+			key := readEmoji()
+			gi.addEmoji(key, out.emoji[key].subgroup)
+		}
+		out.groups[i] = gi
+	}
+	for i, count := 0, r.count(); i < count; i++ {
+		readEmoji()
+	}
+
+	out.declared.Groups = readCounts[string](r)
+	out.declared.GroupsWithoutModifiers = readCounts[string](r)
+	out.declared.Status = readCounts[tr51.Property](r)
+
+	if err := r.done(); err != nil {
+		return err
+	}
+	*t = out
+	return nil
+}
+
+// MarshalBinary returns a compact snapshot of this Cats.
+func (im *Cats) MarshalBinary() ([]byte, error) {
+	w := newSnapshotWriter()
+
+	w.uint(uint64(len(im.vendors)))
+	for _, title := range im.vendors {
+		w.string(title)
+	}
+
+	keys := make([]string, 0, len(im.emoji))
+	for key := range im.emoji {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	w.uint(uint64(len(keys)))
+	for _, key := range keys {
+		titles := im.emoji[key]
+		w.string(key)
+		w.uint(uint64(len(titles)))
+		for _, title := range titles {
+			w.string(title)
+		}
+	}
+
+	return w.finish(snapshotCats), nil
+}
+
+// UnmarshalBinary replaces this Cats with a snapshot from MarshalBinary.
+func (im *Cats) UnmarshalBinary(b []byte) error {
+	r, err := newSnapshotReader(b, snapshotCats)
+	if err != nil {
+		return err
+	}
+
+	var out Cats
+	for i, count := 0, r.count(); i < count; i++ {
+		out.vendors = append(out.vendors, r.string())
+	}
+
+	count := r.count()
+	out.emoji = make(map[string][]string, count)
+	for i := 0; i < count; i++ {
+		key := r.string()
+		titles := make([]string, r.count())
+		for j := range titles {
+			titles[j] = r.string()
+		}
+		out.emoji[key] = titles
+	}
+
+	if err := r.done(); err != nil {
+		return err
+	}
+	*im = out
+	return nil
+}
@@ -0,0 +1,140 @@
+package emoji
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+// readTestData returns a sample of emoji-test.txt with a few whole groups, and a fake
+// emoji-data.txt built from it.
+func readTestData(tb testing.TB) (test, data []byte) {
+	test, err := os.ReadFile("testdata/emoji-test.txt")
+	if err != nil {
+		tb.Fatalf("couldn't read emoji-test.txt: %v", err)
+	}
+
+	var b strings.Builder
+	err = tr51.ReadFunc(bytes.NewReader(test), func(l tr51.Line) error {
+		if l.Single != 0 && l.HasProperty(tr51.PropertyFullyQualified) {
+			fmt.Fprintf(&b, "%04X ; Emoji # E%v [1] (%s) %s\n", l.Single, l.EmojiVersion, l.GlyphLow, l.Notes)
+			fmt.Fprintf(&b, "%04X ; Emoji_Presentation # E%v [1] (%s) %s\n", l.Single, l.EmojiVersion, l.GlyphLow, l.Notes)
+		}
+		return nil
+	})
+	if err != nil {
+		tb.Fatalf("couldn't build emoji-data.txt: %v", err)
+	}
+	return test, []byte(b.String())
+}
+
+func TestSnapshot(t *testing.T) {
+	rawTest, rawData := readTestData(t)
+
+	et, err := NewTest(tr51.NewReader(bytes.NewReader(rawTest)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+	ed, err := NewData(tr51.NewReader(bytes.NewReader(rawData)))
+	if err != nil {
+		t.Fatalf("couldn't NewData: %v", err)
+	}
+	cats, err := NewCats(tr51.NewReader(bytes.NewBufferString("# Title\n1F600\n1F601 1F602\n# Other\n1F600\n")))
+	if err != nil {
+		t.Fatalf("couldn't NewCats: %v", err)
+	}
+
+	type snapshotter interface {
+		MarshalBinary() ([]byte, error)
+		UnmarshalBinary([]byte) error
+	}
+	all := []struct {
+		in, out snapshotter
+	}{
+		{et, &Test{}},
+		{ed, &Data{}},
+		{cats, &Cats{}},
+	}
+	for _, s := range all {
+		b, err := s.in.MarshalBinary()
+		if err != nil {
+			t.Fatalf("couldn't MarshalBinary: %v", err)
+		}
+		if err := s.out.UnmarshalBinary(b); err != nil {
+			t.Fatalf("couldn't UnmarshalBinary: %v", err)
+		}
+		if !reflect.DeepEqual(s.in, s.out) {
+			t.Errorf("expected %T to round-trip", s.in)
+		}
+	}
+
+	b, _ := et.MarshalBinary()
+	if len(b) >= len(rawTest)/2 {
+		t.Errorf("expected snapshot to be compact, was %d bytes vs %d", len(b), len(rawTest))
+	}
+
+	bad := map[string][]byte{
+		"empty":     nil,
+		"wrong":     []byte("not a snapshot"),
+		"truncated": b[:len(b)-1],
+		"corrupt":   append(append([]byte(nil), b[:len(b)-1]...), b[len(b)-1]^1),
+	}
+	for name, raw := range bad {
+		if err := (&Test{}).UnmarshalBinary(raw); !errors.Is(err, ErrSnapshot) {
+			t.Errorf("%s: expected ErrSnapshot, was %v", name, err)
+		}
+	}
+	if err := (&Data{}).UnmarshalBinary(b); !errors.Is(err, ErrSnapshot) {
+		t.Errorf("expected ErrSnapshot for wrong kind, was %v", err)
+	}
+}
+
+func BenchmarkTestParse(b *testing.B) {
+	rawTest, _ := readTestData(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewTest(tr51.NewReader(bytes.NewReader(rawTest))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTestSnapshot(b *testing.B) {
+	rawTest, _ := readTestData(b)
+	et, _ := NewTest(tr51.NewReader(bytes.NewReader(rawTest)))
+	snapshot, _ := et.MarshalBinary()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := (&Test{}).UnmarshalBinary(snapshot); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDataParse(b *testing.B) {
+	_, rawData := readTestData(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewData(tr51.NewReader(bytes.NewReader(rawData))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDataSnapshot(b *testing.B) {
+	_, rawData := readTestData(b)
+	ed, _ := NewData(tr51.NewReader(bytes.NewReader(rawData)))
+	snapshot, _ := ed.MarshalBinary()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := (&Data{}).UnmarshalBinary(snapshot); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package emoji
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/samthor/tr51"
+)
+
+// Tables contains parsed TR51 data as static values, so it can be compiled into a binary. The
+// tablegen command writes these as Go source.
+type Tables struct {
+	Properties map[tr51.Property]*unicode.RangeTable // from emoji-data.txt
+	Versions   []VersionTable                        // from emoji-data.txt
+	Test       []TestLine                            // from emoji-test.txt
+	TestCounts TestCounts                            // summaries from emoji-test.txt
+	Sequences  []Sequence                            // from emoji-sequences.txt and emoji-zwj-sequences.txt
+}
+
+// VersionTable contains the emoji first found in a version.
+type VersionTable struct {
+	Version tr51.Version
+	Table   *unicode.RangeTable
+}
+
+// NewTables returns Tables containing the passed data, any of which may be nil.
+func NewTables(data *Data, test *Test, sequences *Sequences) *Tables {
+	t := &Tables{}
+
+	if data != nil {
+		t.Properties = make(map[tr51.Property]*unicode.RangeTable, len(data.sets))
+		for p, set := range data.sets {
+			t.Properties[p] = set.RangeTable()
+		}
+
+		versions := make(map[tr51.Version]*tr51.RuneSet)
+		for r, d := range data.emoji {
+			if d.version == (tr51.Version{}) {
+				continue
+			}
+			set, ok := versions[d.version]
+			if !ok {
+				set = &tr51.RuneSet{}
+				versions[d.version] = set
+			}
+			set.Add(r, r)
+		}
+		for v, set := range versions {
+			t.Versions = append(t.Versions, VersionTable{Version: v, Table: set.RangeTable()})
+		}
+		slices.SortFunc(t.Versions, func(a, b VersionTable) int {
+			return a.Version.Compare(b.Version)
+		})
+	}
+
+	if test != nil {
+		for _, gi := range test.groups {
+			for _, emoji := range gi.emoji {
+				t.Test = append(t.Test, test.emoji[emoji].variants...)
+			}
+		}
+		t.TestCounts = test.Declared()
+	}
+
+	if sequences != nil {
+		t.Sequences = slices.Clone(sequences.all)
+	}
+
+	return t
+}
+
+// NewDataFromTables returns a new Data struct built from the emoji-data.txt part of Tables.
+func NewDataFromTables(t *Tables) *Data {
+	ed := &Data{
+		emoji: make(map[rune]emojiData),
+		sets:  make(map[tr51.Property]*tr51.RuneSet, len(t.Properties)),
+	}
+	for p, table := range t.Properties {
+		set := tr51.RuneSetFromTable(table)
+		ed.sets[p] = &set
+	}
+
+	each := func(p tr51.Property, fn func(r rune, d *emojiData)) {
+		set, ok := ed.sets[p]
+		if !ok {
+			return
+		}
+		for _, rr := range set.Ranges() {
+			for r := rr.Low; r <= rr.High; r++ {
+				d := ed.emoji[r]
+				fn(r, &d)
+				ed.emoji[r] = d
+			}
+		}
+	}
+
+	// nb. matches NewData, where emoji with default presentation don't need VS16
+	each(tr51.PropertyEmoji, func(r rune, d *emojiData) { d.unqualified = true })
+	each(tr51.PropertyEmojiPresentation, func(r rune, d *emojiData) { d.unqualified = false })
+	each(tr51.PropertyEmojiModifierBase, func(r rune, d *emojiData) { d.modifierBase = true })
+
+	for _, vt := range t.Versions {
+		set := tr51.RuneSetFromTable(vt.Table)
+		for _, rr := range set.Ranges() {
+			for r := rr.Low; r <= rr.High; r++ {
+				if d, ok := ed.emoji[r]; ok {
+					d.version = vt.Version
+					ed.emoji[r] = d
+				}
+			}
+		}
+	}
+
+	for _, d := range ed.emoji {
+		if d.unqualified {
+			ed.unqualified++
+		}
+	}
+	return ed
+}
+
+// NewTestFromTables returns a new Test struct built from the emoji-test.txt part of Tables.
+func NewTestFromTables(t *Tables) *Test {
+	et := &Test{
+		emoji:    make(map[string]emojiTest, len(t.Test)),
+		declared: t.TestCounts,
+	}
+
+	for _, line := range t.Test {
+		et.add(line)
+	}
+
+	return et
+}
+
+// NewSequencesFromTables returns a new Sequences struct built from the sequences part of Tables.
+func NewSequencesFromTables(t *Tables) *Sequences {
+	s := &Sequences{
+		emoji: make(map[string]int, len(t.Sequences)),
+		all:   slices.Clone(t.Sequences),
+	}
+	for i, seq := range s.all {
+		s.emoji[tr51.Unqualify(seq.Emoji)] = i
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package emoji
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestTables(t *testing.T) {
+	data := `
+2640          ; Emoji                #  1.1  [1] (♀️)       female sign
+231A..231B    ; Emoji                # E0.6  [2] (⌚..⌛)    watch..hourglass done
+1F93C..1F93E  ; Emoji                # E3.0  [3] (🤼..🤾)    people wrestling..person playing handball
+231A..231B    ; Emoji_Presentation   # E0.6  [2] (⌚..⌛)    watch..hourglass done
+1F93C..1F93E  ; Emoji_Presentation   # E3.0  [3] (🤼..🤾)    people wrestling..person playing handball
+1F93D..1F93E  ; Emoji_Modifier_Base  # E3.0  [2] (🤽..🤾)    person playing water polo..person playing handball
+`
+	test := `
+# group: Smileys & Emotion
+1F600                                      ; fully-qualified     # 😀 E1.0 grinning face
+# group: Activities
+1F93C                                      ; fully-qualified     # 🤼 E3.0 people wrestling
+26F9 FE0F                                  ; fully-qualified     # ⛹️ E0.7 person bouncing ball
+26F9                                       ; unqualified         # ⛹ E0.7 person bouncing ball
+`
+	sequences := `
+0023 FE0F 20E3; Emoji_Keycap_Sequence        ; keycap: \x{23}                                                 # E0.6   [1] (#️⃣)
+`
+
+	reader := func(raw string) *tr51.Reader {
+		return tr51.NewReader(bytes.NewBufferString(raw))
+	}
+	ed, err := NewData(reader(data))
+	if err != nil {
+		t.Fatalf("couldn't NewData: %v", err)
+	}
+	et, err := NewTest(reader(test))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+	es, err := NewSequences(reader(sequences))
+	if err != nil {
+		t.Fatalf("couldn't NewSequences: %v", err)
+	}
+
+	tables := NewTables(ed, et, es)
+	if len(tables.Versions) != 3 {
+		t.Errorf("expected 3 versions, was %+v", tables.Versions)
+	}
+
+	if actual := NewDataFromTables(tables); !reflect.DeepEqual(actual, ed) {
+		t.Errorf("expected %+v, was %+v", ed, actual)
+	}
+	if actual := NewTestFromTables(tables); !reflect.DeepEqual(actual, et) {
+		t.Errorf("expected %+v, was %+v", et, actual)
+	}
+	if actual := NewSequencesFromTables(tables); !reflect.DeepEqual(actual, es) {
+		t.Errorf("expected %+v, was %+v", es, actual)
+	}
+}
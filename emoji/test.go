@@ -2,7 +2,7 @@ package emoji
 
 import (
 	"io"
-	"strings"
+	"slices"
 
 	"github.com/samthor/tr51"
 )
@@ -10,27 +10,54 @@ import (
 type emojiTest struct {
 	qualified string
 	notes     string
+	subgroup  string
+	order     int        // position used by Compare, or -1 if unordered
+	variants  []TestLine // every line for this emoji, including qualified
 }
 
 type groupInfo struct {
-	name  string
-	emoji []string
+	name      string
+	emoji     []string
+	subgroups []string // in file order
+}
+
+// addEmoji adds an unqualified emoji and its subgroup to this group.
+func (gi *groupInfo) addEmoji(emoji, subgroup string) {
+	gi.emoji = append(gi.emoji, emoji)
+	if subgroup != "" && !slices.Contains(gi.subgroups, subgroup) {
+		gi.subgroups = append(gi.subgroups, subgroup)
+	}
+}
+
+// TestLine contains a single emoji line from emoji-test.txt, along with its group and subgroup.
+type TestLine struct {
+	Emoji    string        `json:"emoji"`
+	Status   tr51.Property `json:"status,omitempty"` // e.g., fully-qualified or component
+	Notes    string        `json:"notes,omitempty"`
+	Group    string        `json:"group,omitempty"`
+	Subgroup string        `json:"subgroup,omitempty"`
+	Version  tr51.Version  `json:"version"` // see tr51.Line.EffectiveVersion
 }
 
 // Test wraps parsed data from emoji-test.txt.
 type Test struct {
-	emoji  map[string]emojiTest
-	groups []*groupInfo
+	emoji    map[string]emojiTest
+	groups   []*groupInfo
+	declared TestCounts // summaries from the file itself
 }
 
 // NewTest returns a new Test struct, which helps match complex emoji parts. Expects emoji-test.txt
-// from Emoji 4.0+.
+// from Emoji 4.0+, and returns ErrWrongKind if passed any other known kind of data.
 func NewTest(r *tr51.Reader) (*Test, error) {
+	if err := checkKind(r, tr51.KindTest); err != nil {
+		return nil, err
+	}
+
 	t := &Test{
 		emoji: make(map[string]emojiTest),
 	}
 
-	var currentGroup *groupInfo
+	var inStatus bool
 	for {
 		l, err := r.Read()
 		if err == io.EOF {
@@ -39,42 +66,71 @@ func NewTest(r *tr51.Reader) (*Test, error) {
 			return nil, err
 		}
 
-		if !l.HasEmoji() {
-			parts := strings.Split(l.Notes, ": ")
-			if len(parts) != 2 {
-				continue
-			}
-			switch parts[0] {
-			case "group":
-				currentGroup = &groupInfo{name: parts[1]}
-				t.groups = append(t.groups, currentGroup)
-			}
+		if l.Kind == tr51.LineComment {
+			t.declared.parseSummary(l.Notes, &inStatus)
 			continue
-		}
-
-		// non-fully-qualified normally succeeds fully-qualified, but old versions don't always have it
-		seq := l.AsSequence()
-		qualified := string(seq)
-		unqualified := tr51.Unqualify(qualified)
-		if _, ok := t.emoji[unqualified]; ok {
+		} else if l.Kind == tr51.LineGroup {
+			t.groups = append(t.groups, &groupInfo{name: l.Group})
+			continue
+		} else if l.Kind != tr51.LineData {
 			continue
 		}
 
-		test := emojiTest{notes: l.Notes, qualified: qualified}
-		t.emoji[unqualified] = test
-		if currentGroup != nil {
-			currentGroup.emoji = append(currentGroup.emoji, unqualified)
+		line := TestLine{
+			Emoji:    string(l.AsSequence()),
+			Notes:    l.Notes,
+			Group:    l.Group,
+			Subgroup: l.Subgroup,
+			Version:  l.EffectiveVersion(),
+		}
+		if len(l.Properties) != 0 {
+			line.Status = tr51.Property(l.Properties[0])
 		}
+		t.add(line)
 	}
 
 	return t, nil
 }
 
+// add adds a single line of emoji-test.txt, starting a new group unless it matches the last one.
+// Emoji without a group aren't enumerated by TestEach.
+func (t *Test) add(line TestLine) {
+	// non-fully-qualified normally succeeds fully-qualified, but old versions don't always have it
+	unqualified := tr51.Unqualify(line.Emoji)
+	test, ok := t.emoji[unqualified]
+	if !ok {
+		test = emojiTest{notes: line.Notes, qualified: line.Emoji, subgroup: line.Subgroup, order: len(t.emoji)}
+
+		if line.Group != "" {
+			if len(t.groups) == 0 || t.groups[len(t.groups)-1].name != line.Group {
+				t.groups = append(t.groups, &groupInfo{name: line.Group})
+			}
+			t.groups[len(t.groups)-1].addEmoji(unqualified, line.Subgroup)
+		}
+	}
+	test.variants = append(test.variants, line)
+	t.emoji[unqualified] = test
+}
+
 // TestEach contains data about each emoji.
 type TestEach struct {
-	Emoji string
-	Notes string
-	Group string
+	Emoji    string
+	Notes    string
+	Group    string
+	Subgroup string
+	Status   tr51.Property
+	Version  tr51.Version
+}
+
+// TestEachOpts controls which emoji TestEachWithOptions enumerates.
+type TestEachOpts struct {
+	// Variants includes every form of each emoji, such as minimally-qualified and unqualified,
+	// rather than only the first, which is normally fully-qualified.
+	Variants bool
+
+	// Status, if set, includes only forms with one of these statuses, e.g., component. This
+	// implies Variants.
+	Status []tr51.Property
 }
 
 // Groups returns an array of the groups inside the TR51 data.
@@ -86,8 +142,30 @@ func (t *Test) Groups() []string {
 	return out
 }
 
+// Subgroups returns the subgroups of the passed group, in file order.
+func (t *Test) Subgroups(group string) []string {
+	var out []string
+	for _, gi := range t.groups {
+		if gi.name == group {
+			out = append(out, gi.subgroups...)
+		}
+	}
+	return out
+}
+
+// Subgroup returns the subgroup of a single emoji. An empty subgroup means there's no match, or
+// the emoji has no subgroup.
+func (t *Test) Subgroup(s string) string {
+	return t.emoji[tr51.Unqualify(s)].subgroup
+}
+
 // TestEach enumerates through all found emoji in Test.
 func (t *Test) TestEach(fn func(*TestEach)) {
+	t.TestEachWithOptions(TestEachOpts{}, fn)
+}
+
+// TestEachWithOptions enumerates through found emoji in Test, as controlled by opts.
+func (t *Test) TestEachWithOptions(opts TestEachOpts, fn func(*TestEach)) {
 	var each TestEach
 
 	// nb. we use group to provide consistent ordering through file
@@ -95,11 +173,24 @@ func (t *Test) TestEach(fn func(*TestEach)) {
 		for _, emoji := range gi.emoji {
 			test := t.emoji[emoji]
 
-			each.Emoji = test.qualified
-			each.Notes = test.notes
-			each.Group = gi.name
+			variants := test.variants[:1]
+			if opts.Variants || len(opts.Status) != 0 {
+				variants = test.variants
+			}
+			for _, line := range variants {
+				if len(opts.Status) != 0 && !slices.Contains(opts.Status, line.Status) {
+					continue
+				}
+
+				each.Emoji = line.Emoji
+				each.Notes = line.Notes
+				each.Group = gi.name
+				each.Subgroup = test.subgroup
+				each.Status = line.Status
+				each.Version = line.Version
 
-			fn(&each)
+				fn(&each)
+			}
 		}
 	}
 }
@@ -161,3 +252,19 @@ func (t *Test) Split(s string) []string {
 func (t *Test) Name(s string) string {
 	return t.emoji[tr51.Unqualify(s)].notes
 }
+
+// Status returns the status of this exact form of an emoji, e.g., minimally-qualified. An empty
+// status means there's no match.
+func (t *Test) Status(s string) tr51.Property {
+	for _, line := range t.emoji[tr51.Unqualify(s)].variants {
+		if line.Emoji == s {
+			return line.Status
+		}
+	}
+	return ""
+}
+
+// Variants returns every form of the passed emoji, in file order.
+func (t *Test) Variants(s string) []TestLine {
+	return slices.Clone(t.emoji[tr51.Unqualify(s)].variants)
+}
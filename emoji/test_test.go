@@ -68,3 +68,121 @@ func TestTest(t *testing.T) {
 		}
 	}
 }
+
+func TestTestSubgroups(t *testing.T) {
+	raw := `
+# group: Smileys & Emotion
+# subgroup: face-smiling
+1F600                                      ; fully-qualified     # 😀 E1.0 grinning face
+1F603                                      ; fully-qualified     # 😃 E0.6 grinning face with big eyes
+# subgroup: face-affection
+1F970                                      ; fully-qualified     # 🥰 E11.0 smiling face with hearts
+# group: People & Body
+# subgroup: hand-fingers-open
+1F44B                                      ; fully-qualified     # 👋 E0.6 waving hand
+# group: Flags
+`
+
+	et, err := NewTest(tr51.NewReader(bytes.NewBufferString(raw)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+
+	// groups without emoji are still listed
+	groups := []string{"Smileys & Emotion", "People & Body", "Flags"}
+	if actual := et.Groups(); !reflect.DeepEqual(actual, groups) {
+		t.Errorf("expected %v, was %v", groups, actual)
+	}
+
+	subgroups := map[string][]string{
+		"Smileys & Emotion": {"face-smiling", "face-affection"},
+		"People & Body":     {"hand-fingers-open"},
+		"Flags":             nil,
+	}
+	for group, expected := range subgroups {
+		if actual := et.Subgroups(group); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("for %s, expected %v, was %v", group, expected, actual)
+		}
+	}
+
+	if actual := et.Subgroup("🥰"); actual != "face-affection" {
+		t.Errorf("expected face-affection, was %v", actual)
+	}
+	if actual := et.Subgroup("🫨"); actual != "" {
+		t.Errorf("expected no subgroup, was %v", actual)
+	}
+
+	var order []string
+	et.TestEach(func(each *TestEach) {
+		order = append(order, each.Emoji+" "+each.Subgroup)
+	})
+	expected := []string{"😀 face-smiling", "😃 face-smiling", "🥰 face-affection", "👋 hand-fingers-open"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, was %v", expected, order)
+	}
+}
+
+func TestTestStatus(t *testing.T) {
+	raw := `
+# group: Smileys & Emotion
+# subgroup: face-affection
+263A FE0F                                  ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                       ; unqualified         # ☺ E0.6 smiling face
+# subgroup: face-hat
+1F636 200D 1F32B FE0F                      ; fully-qualified     # 😶‍🌫️ E13.1 face in clouds
+1F636 200D 1F32B                           ; minimally-qualified # 😶‍🌫 E13.1 face in clouds
+# group: Component
+# subgroup: skin-tone
+1F3FB                                      ; component           # 🏻 E1.0 light skin tone
+`
+
+	et, err := NewTest(tr51.NewReader(bytes.NewBufferString(raw)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+
+	statuses := map[string]tr51.Property{
+		"☺️":   tr51.PropertyFullyQualified,
+		"☺":    tr51.PropertyUnqualified,
+		"😶‍🌫️": tr51.PropertyFullyQualified,
+		"😶‍🌫":  tr51.PropertyMinimallyQualified,
+		"🏻":    tr51.PropertyComponent,
+		"🫨":    "",
+	}
+	for emoji, expected := range statuses {
+		if actual := et.Status(emoji); actual != expected {
+			t.Errorf("for %s, expected %v, was %v", emoji, expected, actual)
+		}
+	}
+
+	variants := et.Variants("☺")
+	if len(variants) != 2 || variants[1].Status != tr51.PropertyUnqualified {
+		t.Errorf("expected two variants, was %+v", variants)
+	}
+	if expected := (tr51.Version{Major: 0, Minor: 6}); variants[0].Version != expected {
+		t.Errorf("expected version %v, was %v", expected, variants[0].Version)
+	}
+
+	type testOpts struct {
+		opts     TestEachOpts
+		expected []string
+	}
+	all := []testOpts{
+		{TestEachOpts{}, []string{"☺️", "😶‍🌫️", "🏻"}},
+		{TestEachOpts{Variants: true}, []string{"☺️", "☺", "😶‍🌫️", "😶‍🌫", "🏻"}},
+		{TestEachOpts{Status: []tr51.Property{tr51.PropertyComponent}}, []string{"🏻"}},
+		{TestEachOpts{Status: []tr51.Property{tr51.PropertyMinimallyQualified, tr51.PropertyUnqualified}}, []string{"☺", "😶‍🌫"}},
+	}
+	for _, to := range all {
+		var actual []string
+		et.TestEachWithOptions(to.opts, func(each *TestEach) {
+			actual = append(actual, each.Emoji)
+			if et.Status(each.Emoji) != each.Status {
+				t.Errorf("for %s, expected status %v, was %v", each.Emoji, et.Status(each.Emoji), each.Status)
+			}
+		})
+		if !reflect.DeepEqual(actual, to.expected) {
+			t.Errorf("for %+v, expected %v, was %v", to.opts, to.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package emoji
+
+import (
+	"io"
+
+	"github.com/samthor/tr51"
+)
+
+const (
+	variationText  = 1 << iota // has a standardized text style sequence, with VS15
+	variationEmoji             // has a standardized emoji style sequence, with VS16
+)
+
+// Variations wraps parsed data from emoji-variation-sequences.txt.
+type Variations struct {
+	styles map[rune]int
+}
+
+// NewVariations returns a new Variations struct, which describes the bases that have standardized
+// text or emoji style variation sequences. Expects emoji-variation-sequences.txt, and returns
+// ErrWrongKind if passed any other known kind of data.
+func NewVariations(r *tr51.Reader) (*Variations, error) {
+	if err := checkKind(r, tr51.KindVariationSequences); err != nil {
+		return nil, err
+	}
+
+	v := &Variations{
+		styles: make(map[rune]int),
+	}
+
+	for {
+		l, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(l.Sequence) != 2 {
+			continue
+		}
+		base := l.Sequence[0]
+
+		switch {
+		case l.Sequence[1] == runeVS15 || l.HasProperty(tr51.PropertyTextStyle):
+			v.styles[base] |= variationText
+		case l.Sequence[1] == runeVS16 || l.HasProperty(tr51.PropertyEmojiStyle):
+			v.styles[base] |= variationEmoji
+		}
+	}
+
+	return v, nil
+}
+
+// HasText returns whether the passed base has a standardized text style sequence.
+func (v *Variations) HasText(r rune) bool {
+	return v.styles[r]&variationText != 0
+}
+
+// HasEmoji returns whether the passed base has a standardized emoji style sequence.
+func (v *Variations) HasEmoji(r rune) bool {
+	return v.styles[r]&variationEmoji != 0
+}
+
+// Bases returns all runes which have any standardized variation sequence.
+func (v *Variations) Bases() []rune {
+	out := make([]rune, 0, len(v.styles))
+	for r := range v.styles {
+		out = append(out, r)
+	}
+	runeSlice(out).Sort()
+	return out
+}
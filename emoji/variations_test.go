@@ -0,0 +1,52 @@
+package emoji
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestVariations(t *testing.T) {
+	raw := `# emoji-variation-sequences.txt
+231A FE0E  ; text style;  # (1.1) WATCH
+231A FE0F  ; emoji style; # (1.1) WATCH
+267E FE0E  ; text style;  # (4.1) PERMANENT PAPER SIGN
+267E FE0F  ; emoji style; # (4.1) PERMANENT PAPER SIGN
+`
+
+	v, err := NewVariations(tr51.NewReader(bytes.NewBufferString(raw)))
+	if err != nil {
+		t.Fatalf("couldn't NewVariations: %v", err)
+	}
+
+	if !v.HasText(0x231a) || !v.HasEmoji(0x231a) {
+		t.Errorf("expected watch to have both styles")
+	}
+	if v.HasText(0x1f680) || v.HasEmoji(0x1f680) {
+		t.Errorf("expected rocket to have no styles")
+	}
+	if expected := []rune{0x231a, 0x267e}; !reflect.DeepEqual(v.Bases(), expected) {
+		t.Errorf("expected %v, was %v", expected, v.Bases())
+	}
+
+	data := `
+231A..231B    ; Emoji                #  1.1  [2] (⌚..⌛)    watch..hourglass done
+231A..231B    ; Emoji_Presentation   #  1.1  [2] (⌚..⌛)    watch..hourglass done
+267E..267F    ; Emoji                #  4.1  [2] (♾️..♿)    infinity..wheelchair symbol
+`
+	ed, err := NewData(tr51.NewReader(bytes.NewBufferString(data)))
+	if err != nil {
+		t.Fatalf("couldn't NewData: %v", err)
+	}
+
+	text := StripOpts{Presentation: PresentationText, Variations: v}
+	if actual, expected := ed.Normalize("⌚⌛♾", text), "⌚︎⌛♾︎"; actual != expected {
+		t.Errorf("expected %q, was %q", expected, actual)
+	}
+	emoji := StripOpts{Presentation: PresentationEmoji, Variations: v}
+	if actual, expected := ed.Normalize("⌚⌛♾", emoji), "⌚️⌛♾️"; actual != expected {
+		t.Errorf("expected %q, was %q", expected, actual)
+	}
+}
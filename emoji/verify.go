@@ -0,0 +1,157 @@
+package emoji
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samthor/tr51"
+)
+
+// ErrCount indicates that emoji-test.txt doesn't match its own summaries.
+var ErrCount = errors.New("emoji count mismatch")
+
+const (
+	subtotalSuffix       = " subtotal:"
+	withoutModifiers     = "w/o modifiers"
+	statusCountsHeading  = "Status Counts"
+	statusCountSeparator = " : "
+)
+
+// TestCounts contains the number of lines in emoji-test.txt by group and by status.
+type TestCounts struct {
+	Groups                 map[string]int        // lines in each group
+	GroupsWithoutModifiers map[string]int        // lines in each group without a skin tone modifier
+	Status                 map[tr51.Property]int // lines with each status
+}
+
+// parseSummary records a summary comment from the end of a group or of the file, returning
+// whether it was one. inStatus is set after the "Status Counts" heading.
+func (c *TestCounts) parseSummary(notes string, inStatus *bool) bool {
+	if notes == statusCountsHeading {
+		*inStatus = true
+		if c.Status == nil {
+			c.Status = make(map[tr51.Property]int)
+		}
+		return true
+	}
+
+	if *inStatus {
+		status, count, ok := strings.Cut(notes, statusCountSeparator)
+		if !ok {
+			return false
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			return false
+		}
+		c.Status[tr51.Property(strings.TrimSpace(status))] = n
+		return true
+	}
+
+	group, rest, ok := strings.Cut(notes, subtotalSuffix)
+	if !ok {
+		return false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return false
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false
+	}
+
+	target := &c.Groups
+	if strings.Join(fields[1:], " ") == withoutModifiers {
+		target = &c.GroupsWithoutModifiers
+	}
+	if *target == nil {
+		*target = make(map[string]int)
+	}
+	(*target)[group] = n
+	return true
+}
+
+// Counts returns the number of parsed lines, including every variant of each emoji.
+func (t *Test) Counts() TestCounts {
+	c := TestCounts{
+		Groups:                 make(map[string]int),
+		GroupsWithoutModifiers: make(map[string]int),
+		Status:                 make(map[tr51.Property]int),
+	}
+	for _, test := range t.emoji {
+		for _, line := range test.variants {
+			c.Status[line.Status]++
+			if line.Group == "" {
+				continue
+			}
+			c.Groups[line.Group]++
+			if !strings.ContainsFunc(line.Emoji, IsSkinTone) {
+				c.GroupsWithoutModifiers[line.Group]++
+			}
+		}
+	}
+	return c
+}
+
+// Declared returns the counts summarized by emoji-test.txt itself, as group subtotals and overall
+// status counts. Maps are nil if the file had no such summaries.
+func (t *Test) Declared() TestCounts {
+	return TestCounts{
+		Groups:                 maps.Clone(t.declared.Groups),
+		GroupsWithoutModifiers: maps.Clone(t.declared.GroupsWithoutModifiers),
+		Status:                 maps.Clone(t.declared.Status),
+	}
+}
+
+// Verify compares the parsed lines with the subtotals and status counts declared by
+// emoji-test.txt, returning an error wrapping ErrCount for each mismatch. A missing summary is
+// also a mismatch, as the file was probably truncated.
+func (t *Test) Verify() error {
+	actual := t.Counts()
+	var errs []error
+
+	groups := t.Groups()
+	for _, group := range slices.Sorted(maps.Keys(t.declared.Groups)) {
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	for _, group := range groups {
+		expected, ok := t.declared.Groups[group]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: group %q has no subtotal", ErrCount, group))
+			continue
+		}
+		if actual.Groups[group] != expected {
+			errs = append(errs, fmt.Errorf("%w: group %q has %d lines, expected %d", ErrCount, group, actual.Groups[group], expected))
+		}
+		expected, ok = t.declared.GroupsWithoutModifiers[group]
+		if ok && actual.GroupsWithoutModifiers[group] != expected {
+			errs = append(errs, fmt.Errorf("%w: group %q has %d lines %s, expected %d", ErrCount, group, actual.GroupsWithoutModifiers[group], withoutModifiers, expected))
+		}
+	}
+
+	if t.declared.Status == nil {
+		errs = append(errs, fmt.Errorf("%w: no status counts", ErrCount))
+	} else {
+		statuses := slices.Collect(maps.Keys(t.declared.Status))
+		for status := range actual.Status {
+			if _, ok := t.declared.Status[status]; !ok {
+				statuses = append(statuses, status)
+			}
+		}
+		slices.Sort(statuses)
+		for _, status := range statuses {
+			if actual.Status[status] != t.declared.Status[status] {
+				errs = append(errs, fmt.Errorf("%w: status %v has %d lines, expected %d", ErrCount, status, actual.Status[status], t.declared.Status[status]))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
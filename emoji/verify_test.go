@@ -0,0 +1,122 @@
+package emoji
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestTestVerify(t *testing.T) {
+	raw := `
+# group: Smileys & Emotion
+263A FE0F                                  ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                       ; unqualified         # ☺ E0.6 smiling face
+
+# Smileys & Emotion subtotal:		2
+# Smileys & Emotion subtotal:		2	w/o modifiers
+
+# group: People & Body
+1F44B                                      ; fully-qualified     # 👋 E0.6 waving hand
+1F44B 1F3FB                                ; fully-qualified     # 👋🏻 E1.0 waving hand: light skin tone
+
+# People & Body subtotal:		2
+# People & Body subtotal:		1	w/o modifiers
+
+# Status Counts
+# fully-qualified : 3
+# minimally-qualified : 0
+# unqualified : 1
+
+#EOF
+`
+
+	et, err := NewTest(tr51.NewReader(bytes.NewBufferString(raw)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+	if err := et.Verify(); err != nil {
+		t.Errorf("expected no error, was %v", err)
+	}
+	if actual := et.Declared().Groups["People & Body"]; actual != 2 {
+		t.Errorf("expected 2, was %v", actual)
+	}
+
+	type testCase struct {
+		raw      string
+		expected []string
+	}
+	all := []testCase{
+		{
+			strings.Replace(raw, "1F44B 1F3FB", "# 1F44B 1F3FB", 1),
+			[]string{
+				`group "People & Body" has 1 lines, expected 2`,
+				`status fully-qualified has 2 lines, expected 3`,
+			},
+		},
+		{
+			strings.Replace(raw, "subtotal:\t\t1\tw/o", "subtotal:\t\t2\tw/o", 1),
+			[]string{`group "People & Body" has 1 lines w/o modifiers, expected 2`},
+		},
+		{
+			raw[:strings.Index(raw, "# People & Body subtotal")],
+			[]string{`group "People & Body" has no subtotal`, "no status counts"},
+		},
+	}
+	for _, tc := range all {
+		et, err := NewTest(tr51.NewReader(bytes.NewBufferString(tc.raw)))
+		if err != nil {
+			t.Fatalf("couldn't NewTest: %v", err)
+		}
+		err = et.Verify()
+		if !errors.Is(err, ErrCount) {
+			t.Errorf("expected ErrCount, was %v", err)
+			continue
+		}
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != len(tc.expected) {
+			t.Errorf("expected %d errors, was %q", len(tc.expected), lines)
+			continue
+		}
+		for i, expected := range tc.expected {
+			if !strings.HasSuffix(lines[i], expected) {
+				t.Errorf("expected %q, was %q", expected, lines[i])
+			}
+		}
+	}
+}
+
+func TestTestVerifySample(t *testing.T) {
+	rawTest, _ := readTestData(t)
+
+	et, err := NewTest(tr51.NewReader(bytes.NewReader(rawTest)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+	if err := et.Verify(); err != nil {
+		t.Errorf("expected no error, was %v", err)
+	}
+
+	b, _ := et.MarshalBinary()
+	var loaded Test
+	if err := loaded.UnmarshalBinary(b); err != nil {
+		t.Fatalf("couldn't UnmarshalBinary: %v", err)
+	}
+	if err := loaded.Verify(); err != nil {
+		t.Errorf("expected no error after snapshot, was %v", err)
+	}
+	if err := NewTestFromTables(NewTables(nil, et, nil)).Verify(); err != nil {
+		t.Errorf("expected no error after tables, was %v", err)
+	}
+
+	truncated := rawTest[:bytes.Index(rawTest, []byte("# group: Component"))]
+	et, err = NewTest(tr51.NewReader(bytes.NewReader(truncated)))
+	if err != nil {
+		t.Fatalf("couldn't NewTest: %v", err)
+	}
+	if err := et.Verify(); !errors.Is(err, ErrCount) {
+		t.Errorf("expected ErrCount for truncated file, was %v", err)
+	}
+}
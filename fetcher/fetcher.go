@@ -0,0 +1,203 @@
+// Package fetcher downloads TR51 files for a specific Emoji version, caching them locally and
+// checking them against pinned SHA-256 sums.
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samthor/tr51"
+)
+
+// DefaultBaseURL is where Unicode publishes TR51 files.
+const DefaultBaseURL = "https://unicode.org/Public"
+
+var (
+	// ErrChecksum indicates a file which doesn't match its pinned SHA-256 sum.
+	ErrChecksum = errors.New("checksum mismatch")
+
+	// ErrStatus indicates an unsuccessful HTTP response.
+	ErrStatus = errors.New("unexpected HTTP status")
+
+	// ErrUnpinned indicates a file without a pinned SHA-256 sum, when unpinned files aren't allowed.
+	ErrUnpinned = errors.New("file not pinned")
+)
+
+// Pin identifies a single TR51 file of an Emoji version.
+type Pin struct {
+	Version tr51.Version
+	Name    string // e.g., emoji-test.txt
+}
+
+// DefaultPins contains known SHA-256 sums of published TR51 files, as lowercase hex.
+var DefaultPins = map[Pin]string{
+	{tr51.Version{Major: 15, Minor: 1}, "emoji-test.txt"}: "d876ee249aa28eaa76cfa6dfaa702847a8d13b062aa488d465d0395ee8137ed9",
+}
+
+// Fetcher downloads and caches TR51 files. The zero value is ready to use, but only fetches files
+// with a pinned SHA-256 sum.
+type Fetcher struct {
+	BaseURL       string         // defaults to DefaultBaseURL
+	CacheDir      string         // defaults to "tr51" inside os.UserCacheDir
+	Client        *http.Client   // defaults to http.DefaultClient
+	Pins          map[Pin]string // defaults to DefaultPins
+	AllowUnpinned bool           // whether to fetch files without a pin, which are not checked
+}
+
+// ucdVersions maps Emoji versions without a matching Unicode release to the Unicode version whose
+// UCD contains their emoji-data.txt.
+var ucdVersions = map[tr51.Version]tr51.Version{
+	{Major: 13, Minor: 1}: {Major: 13, Minor: 0},
+}
+
+// URL returns where the passed file for an Emoji version is published under base. Since Emoji
+// 13.0, emoji-data.txt is part of the UCD, and since Emoji 16.0, all files are published under
+// the Unicode version.
+func URL(base string, v tr51.Version, name string) string {
+	base = strings.TrimSuffix(base, "/")
+	switch {
+	case name == "emoji-data.txt" && v.Major >= 13:
+		if ucd, ok := ucdVersions[v]; ok {
+			v = ucd
+		}
+		return fmt.Sprintf("%s/%d.%d.0/ucd/emoji/%s", base, v.Major, v.Minor, name)
+	case v.Major >= 16:
+		return fmt.Sprintf("%s/%d.%d.0/emoji/%s", base, v.Major, v.Minor, name)
+	}
+	return fmt.Sprintf("%s/emoji/%s/%s", base, v, name)
+}
+
+// Fetch returns the contents of the passed file for an Emoji version, e.g., "emoji-test.txt".
+// Files are read from the cache if present and valid, so repeat requests work offline. Returns
+// ErrUnpinned for files without a pin, unless AllowUnpinned is set.
+func (f *Fetcher) Fetch(ctx context.Context, v tr51.Version, name string) ([]byte, error) {
+	if name != filepath.Base(name) || !strings.HasSuffix(name, ".txt") {
+		return nil, fmt.Errorf("invalid TR51 file name: %q", name)
+	}
+	if _, ok := f.pin(v, name); !ok && !f.AllowUnpinned {
+		return nil, fmt.Errorf("%w: %v %v", ErrUnpinned, v, name)
+	}
+
+	dir, err := f.cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	target := filepath.Join(dir, v.String(), name)
+
+	if raw, err := os.ReadFile(target); err == nil && f.check(v, name, raw) == nil {
+		return raw, nil
+	}
+
+	raw, err := f.download(ctx, URL(f.baseURL(), v, name))
+	if err != nil {
+		return nil, err
+	}
+	if err := f.check(v, name, raw); err != nil {
+		return nil, err
+	}
+	if err := writeFile(target, raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// Open returns a Reader for the passed file for an Emoji version, as per Fetch.
+func (f *Fetcher) Open(ctx context.Context, v tr51.Version, name string) (*tr51.Reader, error) {
+	raw, err := f.Fetch(ctx, v, name)
+	if err != nil {
+		return nil, err
+	}
+	return tr51.NewReader(bytes.NewReader(raw)), nil
+}
+
+// pin returns the pinned SHA-256 sum of the passed file, if any.
+func (f *Fetcher) pin(v tr51.Version, name string) (string, bool) {
+	pins := f.Pins
+	if pins == nil {
+		pins = DefaultPins
+	}
+	expected, ok := pins[Pin{v, name}]
+	return expected, ok
+}
+
+// check returns ErrChecksum if the passed file has a pin which doesn't match.
+func (f *Fetcher) check(v tr51.Version, name string, raw []byte) error {
+	expected, ok := f.pin(v, name)
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256(raw)
+	if actual := hex.EncodeToString(sum[:]); actual != strings.ToLower(expected) {
+		return fmt.Errorf("%w: %v %v: expected %v, was %v", ErrChecksum, v, name, expected, actual)
+	}
+	return nil
+}
+
+func (f *Fetcher) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %v: %v", ErrStatus, url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (f *Fetcher) baseURL() string {
+	if f.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return f.BaseURL
+}
+
+func (f *Fetcher) cacheDir() (string, error) {
+	if f.CacheDir != "" {
+		return f.CacheDir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tr51"), nil
+}
+
+// writeFile writes the file via a temporary file, so partial downloads are never cached.
+func writeFile(target string, raw []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
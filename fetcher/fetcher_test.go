@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samthor/tr51"
+)
+
+func TestURL(t *testing.T) {
+	type testData struct {
+		version tr51.Version
+		name    string
+		url     string
+	}
+	data := []testData{
+		{tr51.Version{Major: 5, Minor: 0}, "emoji-data.txt", "https://unicode.org/Public/emoji/5.0/emoji-data.txt"},
+		{tr51.Version{Major: 12, Minor: 1}, "emoji-test.txt", "https://unicode.org/Public/emoji/12.1/emoji-test.txt"},
+		{tr51.Version{Major: 13, Minor: 1}, "emoji-data.txt", "https://unicode.org/Public/13.0.0/ucd/emoji/emoji-data.txt"},
+		{tr51.Version{Major: 13, Minor: 1}, "emoji-test.txt", "https://unicode.org/Public/emoji/13.1/emoji-test.txt"},
+		{tr51.Version{Major: 15, Minor: 1}, "emoji-data.txt", "https://unicode.org/Public/15.1.0/ucd/emoji/emoji-data.txt"},
+		{tr51.Version{Major: 15, Minor: 1}, "emoji-test.txt", "https://unicode.org/Public/emoji/15.1/emoji-test.txt"},
+		{tr51.Version{Major: 16, Minor: 0}, "emoji-data.txt", "https://unicode.org/Public/16.0.0/ucd/emoji/emoji-data.txt"},
+		{tr51.Version{Major: 16, Minor: 0}, "emoji-test.txt", "https://unicode.org/Public/16.0.0/emoji/emoji-test.txt"},
+	}
+	for _, td := range data {
+		if actual := URL(DefaultBaseURL+"/", td.version, td.name); actual != td.url {
+			t.Errorf("expected %v, was %v", td.url, actual)
+		}
+	}
+}
+
+func TestFetcher(t *testing.T) {
+	const raw = "1F600 ; fully-qualified # 😀 E1.0 grinning face\n"
+	sum := sha256.Sum256([]byte(raw))
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path == "/emoji/15.1/emoji-test.txt" || r.URL.Path == "/15.1.0/ucd/emoji/emoji-data.txt" {
+			w.Write([]byte(raw))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	v := tr51.Version{Major: 15, Minor: 1}
+	f := &Fetcher{
+		BaseURL:  server.URL,
+		CacheDir: t.TempDir(),
+		Pins:     map[Pin]string{{v, "emoji-test.txt"}: hex.EncodeToString(sum[:])},
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		out, err := f.Fetch(ctx, v, "emoji-test.txt")
+		if err != nil {
+			t.Fatalf("couldn't Fetch: %v", err)
+		}
+		if string(out) != raw {
+			t.Errorf("expected %q, was %q", raw, out)
+		}
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected a single request, was %v", requests)
+	}
+
+	// unpinned files are only fetched if allowed
+	if _, err := f.Fetch(ctx, v, "emoji-data.txt"); !errors.Is(err, ErrUnpinned) {
+		t.Errorf("expected ErrUnpinned, was %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected no request for unpinned file, was %v", requests)
+	}
+	f.AllowUnpinned = true
+	if _, err := f.Fetch(ctx, v, "emoji-data.txt"); err != nil {
+		t.Errorf("couldn't Fetch unpinned file: %v", err)
+	}
+
+	if _, err := f.Fetch(ctx, v, "emoji-sequences.txt"); !errors.Is(err, ErrStatus) {
+		t.Errorf("expected ErrStatus, was %v", err)
+	}
+
+	// cached files are served offline
+	server.Close()
+	r, err := f.Open(ctx, v, "emoji-test.txt")
+	if err != nil {
+		t.Fatalf("couldn't Open offline: %v", err)
+	}
+	if l, err := r.Read(); err != nil || !l.HasProperty(tr51.PropertyFullyQualified) {
+		t.Errorf("expected fully-qualified line, was %+v (err=%v)", l, err)
+	}
+}
+
+func TestFetcherChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# tampered\n"))
+	}))
+	defer server.Close()
+
+	f := &Fetcher{BaseURL: server.URL, CacheDir: t.TempDir()}
+	v := tr51.Version{Major: 15, Minor: 1}
+	if _, err := f.Fetch(context.Background(), v, "emoji-test.txt"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected ErrChecksum, was %v", err)
+	}
+
+	if _, err := f.Fetch(context.Background(), v, "../emoji-test.txt"); err == nil {
+		t.Errorf("expected bad name to fail")
+	}
+}
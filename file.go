@@ -1,15 +1,45 @@
 package tr51
 
 import (
-	"bufio"
-	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"iter"
 )
 
+// ParseError is returned by Reader for a line that could not be parsed.
+type ParseError struct {
+	Line int    // 1-based line number
+	Raw  []byte // raw line, including any whitespace
+	Err  error  // underlying error e.g., ErrInvalidRange
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v: %q", e.Line, e.Err, e.Raw)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrStop may be returned by the callback passed to ReadFunc or ReadFuncContext to stop reading
+// early without causing an error.
+var ErrStop = errors.New("stop reading")
+
 // ReadFunc reads an io.Reader and passes each Line to the specified method.
 func ReadFunc(r io.Reader, fn func(Line) error) error {
+	return ReadFuncContext(context.Background(), r, fn)
+}
+
+// ReadFuncContext is like ReadFunc, but stops reading once the passed context is done, returning
+// its error.
+func ReadFuncContext(ctx context.Context, r io.Reader, fn func(Line) error) error {
 	er := NewReader(r)
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		line, err := er.Read()
 		if err == io.EOF {
 			return nil
@@ -17,34 +47,124 @@ func ReadFunc(r io.Reader, fn func(Line) error) error {
 			return err
 		}
 		err = fn(line)
-		if err != nil {
+		if errors.Is(err, ErrStop) {
+			return nil
+		} else if err != nil {
 			return err
 		}
 	}
 }
 
+// ReaderOpts controls how a Reader parses TR51 data.
+type ReaderOpts struct {
+	// Strict rejects invalid code points and ranges, duplicate properties and non-numeric
+	// versions, which are otherwise accepted for compatibility with old files.
+	Strict bool
+
+	// UnknownProperty, if set, is called with the first property of each line which is not a
+	// known Property, e.g., to log a warning. Later properties are not checked, as they may be
+	// free-form. Returning an error stops reading with a ParseError wrapping it.
+	UnknownProperty func(p string) error
+}
+
 // Reader allows reading of TR51 data.
 type Reader struct {
-	r *bufio.Reader
+	s       *Scanner
+	pending []Line // lines read ahead by Header
+	err     error  // error found while reading ahead
 }
 
 // NewReader returns a new Reader for TR51 data.
 func NewReader(r io.Reader) *Reader {
-	return &Reader{bufio.NewReader(r)}
+	return NewReaderWithOptions(r, ReaderOpts{})
+}
+
+// NewReaderWithOptions returns a new Reader for TR51 data with the given options.
+func NewReaderWithOptions(r io.Reader, opts ReaderOpts) *Reader {
+	return &Reader{s: NewScannerWithOptions(r, opts)}
+}
+
+// Header returns the metadata declared in the leading comments of the TR51 data. This reads ahead
+// to the first line with emoji if required, but those lines are still returned by Read.
+func (r *Reader) Header() (Header, error) {
+	err := r.readAhead(func() bool { return r.s.data })
+	return r.s.header, err
+}
+
+// Kind returns the kind of TR51 data, detected from its header and first lines with emoji. This
+// reads ahead if required, but those lines are still returned by Read.
+func (r *Reader) Kind() (Kind, error) {
+	err := r.readAhead(func() bool {
+		var count int
+		for _, l := range r.pending {
+			if l.HasEmoji() {
+				count++
+			}
+		}
+		return count >= kindLines
+	})
+	if err != nil {
+		return KindUnknown, err
+	}
+	return detectKind(r.s.header, r.pending), nil
 }
 
 // Read returns the next line of TR51 data. If no line is available, returns an error.
 func (r *Reader) Read() (Line, error) {
-	var empty Line
+	if len(r.pending) > 0 {
+		line := r.pending[0]
+		r.pending = r.pending[1:]
+		return line, nil
+	} else if r.err != nil {
+		return Line{}, r.err
+	}
+	return r.read()
+}
 
-	for {
-		raw, _, err := r.r.ReadLine()
-		if err != nil {
-			return empty, err // including io.EOF
+// All returns an iterator over the remaining lines of TR51 data. Iteration finishes at io.EOF, or
+// after yielding the first other error alongside an empty Line.
+func (r *Reader) All() iter.Seq2[Line, error] {
+	return func(yield func(Line, error) bool) {
+		for {
+			line, err := r.Read()
+			if err == io.EOF || !yield(line, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readAhead reads lines for later calls to Read, until done returns true or there are no more
+// lines.
+func (r *Reader) readAhead(done func() bool) error {
+	for !done() && r.err == nil {
+		line, err := r.read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			r.err = err
+			return err
 		}
-		raw = bytes.TrimSpace(raw)
-		if len(raw) > 0 {
-			return Parse(raw)
+		r.pending = append(r.pending, line)
+	}
+	return r.err
+}
+
+func (r *Reader) read() (Line, error) {
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return Line{}, err
 		}
+		return Line{}, io.EOF
+	}
+
+	// copy slices, as the Scanner reuses them
+	line := *r.s.Line()
+	if line.Properties != nil {
+		line.Properties = append([]string(nil), line.Properties...)
+	}
+	if line.Sequence != nil {
+		line.Sequence = append([]rune(nil), line.Sequence...)
 	}
+	return line, nil
 }
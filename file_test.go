@@ -2,6 +2,10 @@ package tr51
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
 )
 
@@ -33,3 +37,151 @@ func TestReader(t *testing.T) {
 		t.Errorf("expected %d, was %d lines", expected, count)
 	}
 }
+
+func TestReaderLineNumber(t *testing.T) {
+	raw := []byte(`# comment
+
+1F60E                                      ; fully-qualified     # 😎 smiling face with sunglasses
+1F60D..1F60D..1F60D                        ; fully-qualified     # 😍 smiling face with heart-eyes
+`)
+
+	r := NewReader(bytes.NewBuffer(raw))
+	for _, expected := range []int{1, 3} {
+		l, err := r.Read()
+		if err != nil {
+			t.Fatalf("got err: %v", err)
+		}
+		if l.LineNumber != expected {
+			t.Errorf("expected line %d, was %d", expected, l.LineNumber)
+		}
+	}
+
+	_, err := r.Read()
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected ParseError, was %v", err)
+	}
+	if expected := 4; pe.Line != expected {
+		t.Errorf("expected error on line %d, was %d", expected, pe.Line)
+	}
+	if !bytes.HasPrefix(pe.Raw, []byte("1F60D..")) {
+		t.Errorf("expected raw line in error, was %q", pe.Raw)
+	}
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("expected ErrInvalidRange, was %v", err)
+	}
+}
+
+func TestReaderStrict(t *testing.T) {
+	testdata := map[string]error{
+		"ZZZZ ; Emoji":                               ErrInvalidPoint,
+		"1F600..1F5FF ; Emoji":                       ErrReversedRange,
+		"D83D ; Emoji":                               ErrSurrogate,
+		"1F600 DE00 ; fully-qualified":               ErrSurrogate,
+		"D7FF..E000 ; Emoji":                         ErrSurrogate,
+		"110000 ; Emoji":                             ErrOutOfRange,
+		"1F600 ; Emoji ; Emoji":                      ErrDuplicateProperty,
+		"1F600 ; Emoji # E1.x [1] (😀) grinning face": ErrInvalidVersion,
+		"1F600 ; Emoji # V6 (😀) grinning face":       ErrInvalidVersion,
+		"1F600 ; Emoji # 6.1.2 (😀) grinning face":    ErrInvalidVersion,
+		"1F600 ; Emoji # 6. (😀) grinning face":       ErrInvalidVersion,
+		"1F600 ; Emoji # (6.) grinning face":         ErrInvalidVersion,
+	}
+
+	for input, expected := range testdata {
+		strict := NewReaderWithOptions(bytes.NewBufferString(input), ReaderOpts{Strict: true})
+		if _, err := strict.Read(); !errors.Is(err, expected) {
+			t.Errorf("for %s, expected %v, was %v", input, expected, err)
+		}
+
+		lenient := NewReader(bytes.NewBufferString(input))
+		if _, err := lenient.Read(); err != nil {
+			t.Errorf("for %s, expected no err when lenient, was %v", input, err)
+		}
+	}
+
+	valid := `1F442..1F4F7  ; Emoji_Presentation   #  6.0[182] (👂..📷)    ear..camera
+1F61F ;	emoji ;	L1 ;	secondary ;	x	# V6.1 (😟) WORRIED FACE
+0023 FE0E  ; text style;  # (1.1) NUMBER SIGN
+1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face
+`
+	r := NewReaderWithOptions(bytes.NewBufferString(valid), ReaderOpts{Strict: true})
+	for {
+		_, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Errorf("expected valid data in strict mode, was %v", err)
+			break
+		}
+	}
+}
+
+func TestReaderAll(t *testing.T) {
+	raw := `# comment
+1F60E ; fully-qualified # 😎 smiling face with sunglasses
+1F60D ; fully-qualified # 😍 smiling face with heart-eyes
+1F60D..1F60D..1F60D ; fully-qualified
+1F600 ; fully-qualified # 😀 grinning face
+`
+
+	var count int
+	var lastErr error
+	for l, err := range NewReader(bytes.NewBufferString(raw)).All() {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if l.LineNumber != count+1 {
+			t.Errorf("expected line %d, was %d", count+1, l.LineNumber)
+		}
+		count++
+	}
+	if expected := 3; count != expected {
+		t.Errorf("expected %d lines, was %d", expected, count)
+	}
+	if !errors.Is(lastErr, ErrInvalidRange) {
+		t.Errorf("expected ErrInvalidRange, was %v", lastErr)
+	}
+
+	count = 0
+	for range NewReader(bytes.NewBufferString(raw)).All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("expected break to stop iteration")
+	}
+}
+
+func TestReadFuncStop(t *testing.T) {
+	raw := `1F60E ; fully-qualified # 😎 smiling face with sunglasses
+1F60D ; fully-qualified # 😍 smiling face with heart-eyes
+`
+
+	var count int
+	err := ReadFunc(bytes.NewBufferString(raw), func(l Line) error {
+		count++
+		return fmt.Errorf("wrapped: %w", ErrStop)
+	})
+	if err != nil {
+		t.Errorf("expected nil err on ErrStop, was %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 line before stop, was %d", count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count = 0
+	err = ReadFuncContext(ctx, bytes.NewBufferString(raw), func(l Line) error {
+		count++
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, was %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 line before cancel, was %d", count)
+	}
+}
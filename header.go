@@ -0,0 +1,57 @@
+package tr51
+
+import (
+	"strings"
+)
+
+// Header contains metadata declared in the leading comments of a TR51 file.
+type Header struct {
+	File      string  // declared file name e.g., "emoji-data.txt"
+	Date      string  // declared date e.g., "2023-02-01, 13:22:00 GMT"
+	Version   Version // declared version e.g., 15.1
+	Copyright string  // copyright notice e.g., "© 2023 Unicode®, Inc."
+}
+
+// parse updates the header from the notes of a single comment line. Earlier values win.
+func (h *Header) parse(notes string) {
+	key, value, ok := strings.Cut(notes, ":")
+	if ok {
+		value = strings.TrimSpace(value)
+		switch key {
+		case "File":
+			setIfEmpty(&h.File, value)
+		case "Date":
+			setIfEmpty(&h.Date, value)
+		case "Version":
+			h.setVersion(value)
+		}
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(notes, "©") || strings.HasPrefix(notes, "Copyright"):
+		setIfEmpty(&h.Copyright, notes)
+	case strings.HasSuffix(notes, ".txt") && !strings.ContainsAny(notes, " \t"):
+		// e.g., "emoji-data.txt" on the first line
+		setIfEmpty(&h.File, notes)
+	default:
+		// e.g., "Used with Emoji Version 15.1 and subsequent minor revisions (if any)"
+		const versionPrefix = "Emoji Version "
+		if index := strings.Index(notes, versionPrefix); index != -1 {
+			h.setVersion(numberPrefixOf(notes[index+len(versionPrefix):]))
+		}
+	}
+}
+
+// setVersion sets the version if it is not yet set and the passed value is valid.
+func (h *Header) setVersion(value string) {
+	if v, err := ParseVersion(value); err == nil && h.Version == (Version{}) {
+		h.Version = v
+	}
+}
+
+func setIfEmpty(target *string, value string) {
+	if *target == "" {
+		*target = value
+	}
+}
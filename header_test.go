@@ -0,0 +1,97 @@
+package tr51
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHeader(t *testing.T) {
+	testdata := map[string]Header{
+		// Emoji 15.1 emoji-data.txt
+		`# emoji-data.txt
+# Date: 2023-02-01, 13:22:00 GMT
+# © 2023 Unicode®, Inc.
+# For terms of use, see https://www.unicode.org/terms_of_use.html
+#
+# Emoji Data for UTS #51
+# Used with Emoji Version 15.1 and subsequent minor revisions (if any)
+#
+0023          ; Emoji                # E0.0   [1] (#️)       hash sign
+# Version: 99.0
+`: Header{
+			File:      "emoji-data.txt",
+			Date:      "2023-02-01, 13:22:00 GMT",
+			Version:   Version{15, 1},
+			Copyright: "© 2023 Unicode®, Inc.",
+		},
+
+		// Emoji 13.0 emoji-test.txt
+		`# emoji-test.txt
+# Date: 2020-01-21, 13:40:25 GMT
+# © 2020 Unicode®, Inc.
+# Emoji Keyboard/Display Test Data for UTS #51
+# Version: 13.0
+
+# group: Smileys & Emotion
+`: Header{
+			File:      "emoji-test.txt",
+			Date:      "2020-01-21, 13:40:25 GMT",
+			Version:   Version{13, 0},
+			Copyright: "© 2020 Unicode®, Inc.",
+		},
+
+		// Emoji 1.0 emoji-data.txt
+		`# Emoji Data for UTR #51
+#
+# File:    emoji-data.txt
+# Version: 1.0
+# Date:    2015-08-04
+`: Header{
+			File:    "emoji-data.txt",
+			Date:    "2015-08-04",
+			Version: Version{1, 0},
+		},
+
+		"1F60E ; fully-qualified # 😎 smiling face with sunglasses": Header{},
+	}
+
+	for input, expected := range testdata {
+		r := NewReader(bytes.NewBufferString(input))
+		actual, err := r.Header()
+		if err != nil {
+			t.Errorf("got err: %v", err)
+		}
+		if actual != expected {
+			t.Errorf("expected %+v, was %+v", expected, actual)
+		}
+	}
+}
+
+func TestHeaderReadAhead(t *testing.T) {
+	raw := []byte(`# emoji-test.txt
+# Version: 15.1
+1F60E                                      ; fully-qualified     # 😎 smiling face with sunglasses
+1F60D                                      ; fully-qualified     # 😍 smiling face with heart-eyes
+`)
+
+	r := NewReader(bytes.NewBuffer(raw))
+	header, err := r.Header()
+	if err != nil {
+		t.Fatalf("got err: %v", err)
+	}
+	if expected := "emoji-test.txt"; header.File != expected {
+		t.Errorf("expected file %v, was %v", expected, header.File)
+	}
+
+	var notes []string
+	for {
+		l, err := r.Read()
+		if err != nil {
+			break
+		}
+		notes = append(notes, l.Notes)
+	}
+	if expected := 4; len(notes) != expected {
+		t.Errorf("expected %d lines after Header, was %d: %v", expected, len(notes), notes)
+	}
+}
@@ -0,0 +1,155 @@
+package tr51
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonLine is the JSON form of Line. Code points are hex strings as in TR51 data, and unused
+// fields are omitted.
+type jsonLine struct {
+	Point    string   `json:"point,omitempty"`    // e.g., "1F600"
+	Sequence string   `json:"sequence,omitempty"` // e.g., "1F468 200D 1F4BB"
+	Low      string   `json:"low,omitempty"`
+	High     string   `json:"high,omitempty"`
+	Props    []string `json:"properties,omitempty"`
+
+	UnicodeVersion string `json:"unicodeVersion,omitempty"`
+	EmojiVersion   string `json:"emojiVersion,omitempty"`
+	Reserved       bool   `json:"reserved,omitempty"`
+
+	Count     int    `json:"count,omitempty"`
+	GlyphLow  string `json:"glyphLow,omitempty"`
+	GlyphHigh string `json:"glyphHigh,omitempty"`
+	NameLow   string `json:"nameLow,omitempty"`
+	NameHigh  string `json:"nameHigh,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+
+	LineNumber int    `json:"line,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Group      string `json:"group,omitempty"`
+	Subgroup   string `json:"subgroup,omitempty"`
+}
+
+// MarshalJSON encodes this Line with code points as hex strings, omitting unused fields.
+func (lp Line) MarshalJSON() ([]byte, error) {
+	out := jsonLine{
+		Props:      lp.Properties,
+		Reserved:   lp.Reserved,
+		Count:      lp.Count,
+		GlyphLow:   lp.GlyphLow,
+		GlyphHigh:  lp.GlyphHigh,
+		NameLow:    lp.NameLow,
+		NameHigh:   lp.NameHigh,
+		Notes:      lp.Notes,
+		LineNumber: lp.LineNumber,
+		Group:      lp.Group,
+		Subgroup:   lp.Subgroup,
+	}
+	if lp.Kind != LineUnknown {
+		out.Kind = lp.Kind.String()
+	}
+	if lp.Single != 0 {
+		out.Point = formatPoint(lp.Single)
+	}
+	if lp.Low != 0 || lp.High != 0 {
+		out.Low, out.High = formatPoint(lp.Low), formatPoint(lp.High)
+	}
+	if len(lp.Sequence) != 0 {
+		parts := make([]string, len(lp.Sequence))
+		for i, r := range lp.Sequence {
+			parts[i] = formatPoint(r)
+		}
+		out.Sequence = strings.Join(parts, " ")
+	}
+	if lp.UnicodeVersion != (Version{}) {
+		out.UnicodeVersion = lp.UnicodeVersion.String()
+	}
+	if lp.EmojiVersion != (Version{}) {
+		out.EmojiVersion = lp.EmojiVersion.String()
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a Line encoded by MarshalJSON.
+func (lp *Line) UnmarshalJSON(b []byte) error {
+	var in jsonLine
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+
+	out := Line{
+		Properties: in.Props,
+		Reserved:   in.Reserved,
+		Count:      in.Count,
+		GlyphLow:   in.GlyphLow,
+		GlyphHigh:  in.GlyphHigh,
+		NameLow:    in.NameLow,
+		NameHigh:   in.NameHigh,
+		Notes:      in.Notes,
+		LineNumber: in.LineNumber,
+		Group:      in.Group,
+		Subgroup:   in.Subgroup,
+	}
+	for _, p := range out.Properties {
+		out.Props = out.Props.With(Property(p))
+	}
+
+	if in.Kind != "" {
+		index := slices.Index(lineKindNames, in.Kind)
+		if index == -1 {
+			return fmt.Errorf("unknown line kind: %q", in.Kind)
+		}
+		out.Kind = LineKind(index)
+	}
+
+	var err error
+	if in.Point != "" {
+		if out.Single, err = parseJSONPoint(in.Point); err != nil {
+			return err
+		}
+	}
+	if in.Low != "" || in.High != "" {
+		if out.Low, err = parseJSONPoint(in.Low); err != nil {
+			return err
+		}
+		if out.High, err = parseJSONPoint(in.High); err != nil {
+			return err
+		}
+	}
+	for _, part := range strings.Fields(in.Sequence) {
+		r, err := parseJSONPoint(part)
+		if err != nil {
+			return err
+		}
+		out.Sequence = append(out.Sequence, r)
+	}
+	if in.UnicodeVersion != "" {
+		if out.UnicodeVersion, err = ParseVersion(in.UnicodeVersion); err != nil {
+			return err
+		}
+	}
+	if in.EmojiVersion != "" {
+		if out.EmojiVersion, err = ParseVersion(in.EmojiVersion); err != nil {
+			return err
+		}
+	}
+
+	*lp = out
+	return nil
+}
+
+func formatPoint(r rune) string {
+	return fmt.Sprintf("%04X", r)
+}
+
+func parseJSONPoint(s string) (rune, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPoint, s)
+	}
+	return rune(v), nil
+}
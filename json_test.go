@@ -0,0 +1,82 @@
+package tr51
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineJSON(t *testing.T) {
+	testdata := map[string]string{
+		"1F468 200D 1F4BB                                       ; fully-qualified     # 👨‍💻 E4.0 man technologist": `{"sequence":"1F468 200D 1F4BB","properties":["fully-qualified"],"emojiVersion":"4.0","glyphLow":"👨‍💻","nameLow":"man technologist","notes":"man technologist","kind":"data"}`,
+		"2194..2199    ; Emoji                #   [6] (↔️..↙️)  LEFT RIGHT ARROW..SOUTH WEST ARROW":                `{"low":"2194","high":"2199","properties":["Emoji"],"count":6,"glyphLow":"↔️","glyphHigh":"↙️","nameLow":"LEFT RIGHT ARROW","nameHigh":"SOUTH WEST ARROW","notes":"LEFT RIGHT ARROW..SOUTH WEST ARROW","kind":"data"}`,
+		"1F93F         ; Extended_Pictographic#   NA  [1] (🤿️)       <reserved-1F93F>":                             `{"point":"1F93F","properties":["Extended_Pictographic"],"reserved":true,"count":1,"glyphLow":"🤿️","nameLow":"\u003creserved-1F93F\u003e","notes":"\u003creserved-1F93F\u003e","kind":"data"}`,
+		"# group: Smileys & Emotion": `{"notes":"group: Smileys \u0026 Emotion","kind":"group","group":"Smileys \u0026 Emotion"}`,
+		"#":                          `{"kind":"blank"}`,
+	}
+
+	for input, expected := range testdata {
+		l, err := Parse([]byte(input))
+		if err != nil {
+			t.Fatalf("got err: %v", err)
+		}
+		actual, err := json.Marshal(l)
+		if err != nil {
+			t.Fatalf("couldn't marshal: %v", err)
+		}
+		if string(actual) != expected {
+			t.Errorf("expected %s, was %s", expected, actual)
+		}
+	}
+
+	var l Line
+	if err := json.Unmarshal([]byte(`{"point":"zz"}`), &l); err == nil {
+		t.Errorf("expected bad point to fail")
+	}
+}
+
+func TestLineJSONRoundTrip(t *testing.T) {
+	raw := `# emoji-test.txt
+# Version: 15.1
+#
+
+# group: People & Body
+
+# subgroup: hand-fingers-open
+1F44B                                                  ; fully-qualified     # 👋 E0.6 waving hand
+1F44B 1F3FB                                            ; fully-qualified     # 👋🏻 E1.0 waving hand: light skin tone
+1F91A                                                  ; fully-qualified     # 🤚 E3.0 raised back of hand
+1F590 FE0F                                             ; fully-qualified     # 🖐️ E0.7 hand with fingers splayed
+1F590                                                  ; unqualified         # 🖐 E0.7 hand with fingers splayed
+
+# subgroup: person-role
+1F9D1 200D 2695 FE0F                                   ; fully-qualified     # 🧑‍⚕️ E12.1 health worker
+1F9D1 200D 2695                                        ; minimally-qualified # 🧑‍⚕ E12.1 health worker
+
+# People & Body subtotal:		7
+# People & Body subtotal:		6	w/o modifiers
+
+# Status Counts
+# fully-qualified : 5
+# minimally-qualified : 1
+# unqualified : 1
+`
+
+	for l, err := range NewReader(strings.NewReader(raw)).All() {
+		if err != nil {
+			t.Fatalf("got err: %v", err)
+		}
+		b, err := json.Marshal(l)
+		if err != nil {
+			t.Fatalf("couldn't marshal: %v", err)
+		}
+		var actual Line
+		if err := json.Unmarshal(b, &actual); err != nil {
+			t.Fatalf("couldn't unmarshal %s: %v", b, err)
+		}
+		if !reflect.DeepEqual(actual, l) {
+			t.Errorf("line %d: expected %+v, was %+v", l.LineNumber, l, actual)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package tr51
+
+// kindLines is the number of lines with emoji read to detect Kind.
+const kindLines = 16
+
+// Kind is a kind of TR51 data file.
+type Kind int
+
+const (
+	KindUnknown            Kind = iota
+	KindData                    // emoji-data.txt, from Emoji 2.0+
+	KindDataOrigins             // emoji-data.txt from Emoji 1.0, with default style, level and origins
+	KindSequences               // emoji-sequences.txt
+	KindZWJSequences            // emoji-zwj-sequences.txt
+	KindVariationSequences      // emoji-variation-sequences.txt
+	KindTest                    // emoji-test.txt
+	KindCats                    // custom format, where titles in comments precede emoji
+	KindOrdering                // emoji-ordering.txt
+)
+
+var kindNames = []string{
+	KindUnknown:            "unknown",
+	KindData:               "emoji-data",
+	KindDataOrigins:        "emoji-data (origins)",
+	KindSequences:          "emoji-sequences",
+	KindZWJSequences:       "emoji-zwj-sequences",
+	KindVariationSequences: "emoji-variation-sequences",
+	KindTest:               "emoji-test",
+	KindCats:               "cats",
+	KindOrdering:           "emoji-ordering",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return kindNames[KindUnknown]
+	}
+	return kindNames[k]
+}
+
+// detectKind returns the Kind of TR51 data from its header and first lines.
+func detectKind(h Header, lines []Line) Kind {
+	var first Kind
+	var bare bool
+	for _, l := range lines {
+		if !l.HasEmoji() {
+			continue
+		} else if len(l.Properties) == 0 {
+			// only the custom format has emoji without properties
+			bare = true
+		} else if first == KindUnknown {
+			first = kindOfLine(l)
+		}
+	}
+
+	switch h.File {
+	case "emoji-data.txt":
+		if first == KindDataOrigins {
+			return KindDataOrigins
+		}
+		return KindData
+	case "emoji-sequences.txt":
+		return KindSequences
+	case "emoji-zwj-sequences.txt":
+		return KindZWJSequences
+	case "emoji-variation-sequences.txt":
+		return KindVariationSequences
+	case "emoji-test.txt":
+		return KindTest
+	case "emoji-ordering.txt":
+		return KindOrdering
+	}
+
+	if bare {
+		return KindCats
+	}
+	return first
+}
+
+// kindOfLine returns the Kind of TR51 data this Line is from, based on its first property.
+func kindOfLine(l Line) Kind {
+	switch Property(l.Properties[0]) {
+	case PropertyEmoji, PropertyEmojiPresentation, PropertyEmojiModifier, PropertyEmojiModifierBase,
+		PropertyEmojiComponent, PropertyExtendedPictographic:
+		return KindData
+	case PropertyDefaultEmoji, PropertyDefaultText:
+		return KindDataOrigins
+	case PropertyBasicEmoji, PropertyEmojiKeycapSequence, PropertyEmojiCombiningSequence,
+		PropertyRGIEmojiFlagSequence, PropertyEmojiFlagSequence, PropertyRGIEmojiTagSequence,
+		PropertyEmojiTagSequence, PropertyRGIEmojiModifierSequence, PropertyEmojiModifierSequence:
+		return KindSequences
+	case PropertyRGIEmojiZWJSequence, PropertyEmojiZWJSequence:
+		return KindZWJSequences
+	case PropertyTextStyle, PropertyEmojiStyle:
+		return KindVariationSequences
+	case PropertyFullyQualified, PropertyMinimallyQualified, PropertyUnqualified,
+		PropertyNonFullyQualified, PropertyComponent:
+		return KindTest
+	}
+	return KindUnknown
+}
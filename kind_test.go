@@ -0,0 +1,67 @@
+package tr51
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKind(t *testing.T) {
+	testdata := map[string]Kind{
+		"":        KindUnknown,
+		"# blah":  KindUnknown,
+		"1F600 ;": KindUnknown,
+
+		"# emoji-data.txt\n# Version: 15.1\n":                     KindData,
+		"# emoji-test.txt\n# Version: 15.1\n":                     KindTest,
+		"# emoji-ordering.txt\nU+1F600 ; 1.0 # 😀 grinning face\n": KindOrdering,
+
+		`1F93C..1F93E  ; Emoji                #  9.0  [3] (🤼..🤾)    people wrestling..person playing handball`: KindData,
+		`# Emoji Data for UTR #51
+# File:    emoji-data.txt
+# Version: 1.0
+00A9 ;	text ;	L1 ;	none ;	j	# V1.1 (©) COPYRIGHT SIGN`: KindDataOrigins,
+		`0023 FE0F 20E3  ; Emoji_Keycap_Sequence      ; keycap: \x{23}                                                 # E0.6   [1] (#️⃣)`:                           KindSequences,
+		`002A FE0F 20E3; Emoji_Combining_Sequence  ; keycap: *                                                      # 3.0  [1] (*️⃣)`:                                KindSequences,
+		`1F468 200D 2764 FE0F 200D 1F468            ; RGI_Emoji_ZWJ_Sequence  ; couple with heart: man, man                                   # E2.0   [1] (👨‍❤️‍👨)`: KindZWJSequences,
+		`0023 FE0E  ; text style;  # (1.1) NUMBER SIGN`:                                                       KindVariationSequences,
+		`1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face`: KindTest,
+		`0039 20E3                                  ; non-fully-qualified # 9⃣ keycap: 9`:                     KindTest,
+		`1F3F4         ; Emoji_Presentation   #  7.0  [1] (🏴)       black flag
+#CategoryA
+2640          ; Emoji                #  1.1  [1] (♀️)       female sign
+1F93C`: KindCats,
+	}
+
+	for input, expected := range testdata {
+		r := NewReader(bytes.NewBufferString(input))
+		actual, err := r.Kind()
+		if err != nil {
+			t.Errorf("got err: %v", err)
+		}
+		if actual != expected {
+			t.Errorf("for %q, expected %v, was %v", input, expected, actual)
+		}
+	}
+}
+
+func TestKindReadAhead(t *testing.T) {
+	raw := `# emoji-test.txt
+1F60E ; fully-qualified # 😎 smiling face with sunglasses
+1F60D ; fully-qualified # 😍 smiling face with heart-eyes
+`
+	r := NewReader(bytes.NewBufferString(raw))
+	if kind, _ := r.Kind(); kind != KindTest {
+		t.Errorf("expected %v, was %v", KindTest, kind)
+	}
+
+	var count int
+	for _, err := range r.All() {
+		if err != nil {
+			t.Errorf("got err: %v", err)
+		}
+		count++
+	}
+	if expected := 3; count != expected {
+		t.Errorf("expected %d lines after Kind, was %d", expected, count)
+	}
+}
@@ -3,44 +3,77 @@ package tr51
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 var (
-	propertiesSep   = []byte{';'}
-	rangeSep        = []byte("..")
-	keycapHash      = []byte("keycap: #")
-	keycapEscaped   = []byte(`\x{23}`)
-	escapedPrefix   = []byte(`\x`)
-	versionNAPrefix = []byte("NA ")
+	propertiesSep = []byte{';'}
+	rangeSep      = []byte("..")
+	keycapHash    = []byte("keycap: #")
+	pointPrefix   = []byte("U+")
+	escapedPrefix = []byte(`\x`)
 
 	// ErrInvalidRange indicates bad TR51 data.
 	ErrInvalidRange = errors.New("invalid emoji range")
 
-	// ErrUnhandledEscape indicates that an unhandled escape was reached.
-	// This library special-cases escapes as there's not many of them.
+	// ErrInvalidPoint indicates a code point which is not valid hex. Only returned in strict mode.
+	ErrInvalidPoint = errors.New("invalid code point")
+
+	// ErrReversedRange indicates a range where low is above high. Only returned in strict mode.
+	ErrReversedRange = errors.New("reversed emoji range")
+
+	// ErrSurrogate indicates a surrogate code point, or a range containing one. Only returned in
+	// strict mode.
+	ErrSurrogate = errors.New("surrogate code point")
+
+	// ErrOutOfRange indicates a code point above U+10FFFF. Only returned in strict mode.
+	ErrOutOfRange = errors.New("code point out of range")
+
+	// ErrDuplicateProperty indicates a property repeated on one line. Only returned in strict mode.
+	ErrDuplicateProperty = errors.New("duplicate property")
+
+	// ErrInvalidVersion indicates a version which is not numeric. Versions with trailing text are
+	// only rejected in strict mode.
+	ErrInvalidVersion = errors.New("invalid version")
+
+	// ErrUnhandledEscape indicates that a malformed escape was reached. Well-formed escapes of the
+	// form \x{HEX} are always decoded.
 	ErrUnhandledEscape = errors.New("unhandled \\x escape code")
 )
 
 // Line represents all possible raw line parts of a TR51 doc.
 type Line struct {
-	Single     rune     // for single rune emoji
-	Low, High  rune     // for low/high pairs e.g., AAAA..BBBB
-	Sequence   []rune   // for runs of emoji e.g., 1F468 200D 2764 FE0F
-	Version    float32  // unicode version
-	Notes      string   // trailing notes as part of comment
-	Properties []string // ;-separated properties
+	Single     rune        // for single rune emoji
+	Low, High  rune        // for low/high pairs e.g., AAAA..BBBB
+	Sequence   []rune      // for runs of emoji e.g., 1F468 200D 2764 FE0F
+	Notes      string      // trailing notes as part of comment
+	Properties []string    // ;-separated properties
+	Props      PropertySet // known properties from Properties, for fast membership tests
+
+	UnicodeVersion Version // unicode version from comment e.g., V6.1 or 6.1
+	EmojiVersion   Version // emoji version from comment e.g., E0.6
+	Reserved       bool    // whether this is reserved and has no version, i.e., NA
+
+	Count               int    // code point count from comment e.g., [3]
+	GlyphLow, GlyphHigh string // rendered glyphs from comment e.g., (🤼..🤾)
+	NameLow, NameHigh   string // names from notes e.g., people wrestling..person playing handball
+
+	LineNumber int      // 1-based source line number, if read by Reader
+	Kind       LineKind // kind of line, e.g., data or a group comment
+	Group      string   // group in effect, from "# group:" comments, if read by Reader
+	Subgroup   string   // subgroup in effect, from "# subgroup:" comments, if read by Reader
 }
 
 // HasProperty returns whether this line has the given property.
-func (lp *Line) HasProperty(s string) bool {
-	for _, v := range lp.Properties {
-		if s == v {
-			return true
-		}
+func (lp *Line) HasProperty(p Property) bool {
+	if p.Known() {
+		return lp.Props.Has(p)
 	}
-	return false
+	return hasString(lp.Properties, string(p))
 }
 
 // AsSequence returns single or sequenced emoji as a sequence.
@@ -79,13 +112,54 @@ func (lp *Line) Each(fn func([]rune)) {
 	}
 }
 
+// EachShared is like Each, but passes a buffer which is reused between calls rather than
+// allocating one per rune. The passed slice is only valid until the callback returns.
+func (lp *Line) EachShared(fn func([]rune)) {
+	var buf [1]rune
+	if lp.Single != 0 {
+		buf[0] = lp.Single
+		fn(buf[:])
+	}
+	if len(lp.Sequence) != 0 {
+		fn(lp.Sequence)
+	}
+	if lp.High != 0 {
+		for i := lp.Low; i <= lp.High; i++ {
+			buf[0] = i
+			fn(buf[:])
+		}
+	}
+}
+
 // HasEmoji returns whether this line has any emoji parts.
 func (lp *Line) HasEmoji() bool {
 	return lp.Single != 0 || len(lp.Sequence) > 0 || lp.High != 0
 }
 
+// EffectiveVersion returns the emoji version of this line, or its Unicode version for older files
+// which only have that. This is the version kept by the emoji package.
+func (lp *Line) EffectiveVersion() Version {
+	if lp.EmojiVersion != (Version{}) {
+		return lp.EmojiVersion
+	}
+	return lp.UnicodeVersion
+}
+
 // Parse parses a single line of a TR51 doc.
 func Parse(line []byte) (out Line, err error) {
+	err = parseInto(line, &out, false)
+	if err == nil {
+		out.classify()
+	}
+	return out, err
+}
+
+// parseInto parses a single line of a TR51 doc into out, reusing its slices. If strict is set,
+// rejects data which is normally accepted for compatibility with old files.
+func parseInto(line []byte, out *Line, strict bool) (err error) {
+	properties, sequence := out.Properties[:0], out.Sequence[:0]
+	*out = Line{}
+
 	var comment []byte
 	commentIndex := bytes.IndexByte(line, '#')
 	if commentIndex != -1 {
@@ -102,149 +176,297 @@ func Parse(line []byte) (out Line, err error) {
 		line = bytes.TrimSpace(line[:commentIndex])
 	}
 
-	// special-case the escaped keycap
-	// TODO: should support \x{anything}
-	line = bytes.Replace(line, keycapEscaped, []byte{'#'}, -1)
-	if bytes.Index(line, escapedPrefix) != -1 {
-		return out, ErrUnhandledEscape
+	comment, err = unescape(comment)
+	if err != nil {
+		return err
 	}
 
-	left := bytes.Split(line, propertiesSep)
-	for i := range left {
-		left[i] = bytes.TrimSpace(left[i])
+	// nb. unescape each field after splitting, so escaped ';' doesn't create new fields
+	points, rest, hasProperties := bytes.Cut(line, propertiesSep)
+	points, err = unescape(bytes.TrimSpace(points))
+	if err != nil {
+		return err
 	}
-	if len(left) > 1 {
-		out.Properties = make([]string, len(left)-1)
-		for i, v := range left[1:] {
-			out.Properties[i] = string(v)
+	for hasProperties {
+		var field []byte
+		field, rest, hasProperties = bytes.Cut(rest, propertiesSep)
+		field, err = unescape(bytes.TrimSpace(field))
+		if err != nil {
+			return err
+		}
+		p, bit := internProperty(field)
+		if strict && hasString(properties, p) {
+			return fmt.Errorf("%w: %q", ErrDuplicateProperty, p)
 		}
+		properties = append(properties, p)
+		out.Props |= bit
 	}
+	out.Properties = properties
 
 	// extract points
-	points := left[0]
-	if pointParts := bytes.Fields(points); len(pointParts) == 1 {
-		rangeParts := bytes.Split(pointParts[0], rangeSep)
-		if len(rangeParts) == 2 {
+	part, points := cutField(points)
+	if len(part) == 0 {
+		// nothing here, nothing to do
+		out.Notes = string(comment)
+		return nil
+	} else if len(points) == 0 {
+		if low, high, isRange := bytes.Cut(part, rangeSep); isRange {
 			// range e.g. AAAA..BBBB
-			out.Low = parsePoint(rangeParts[0])
-			out.High = parsePoint(rangeParts[1])
-		} else if len(rangeParts) > 2 {
-			return out, ErrInvalidRange
+			if bytes.Contains(high, rangeSep) {
+				return ErrInvalidRange
+			}
+			if out.Low, err = parsePoint(low, strict); err != nil {
+				return err
+			}
+			if out.High, err = parsePoint(high, strict); err != nil {
+				return err
+			}
+			if strict && out.Low > out.High {
+				return fmt.Errorf("%w: %s", ErrReversedRange, part)
+			} else if strict && out.Low <= 0xdfff && out.High >= 0xd800 {
+				return fmt.Errorf("%w: %s", ErrSurrogate, part)
+			}
 		} else {
 			// single point only
-			out.Single = parsePoint(pointParts[0])
+			if out.Single, err = parsePoint(part, strict); err != nil {
+				return err
+			}
 		}
-	} else if len(pointParts) == 0 {
-		// nothing here, nothing to do
-		out.Notes = string(comment)
-		return out, nil
 	} else {
 		// space-separated sequence
-		out.Sequence = make([]rune, len(pointParts))
-		for i, v := range pointParts {
-			out.Sequence[i] = parsePoint(v)
+		for len(part) != 0 {
+			r, err := parsePoint(part, strict)
+			if err != nil {
+				return err
+			}
+			sequence = append(sequence, r)
+			part, points = cutField(points)
 		}
+		out.Sequence = sequence
 	}
 
 	if len(comment) < 2 {
-		return out, nil // weird, but possible
-	}
-
-	// strip V or E if it's part of version
-	if comment[0] == 'V' && unicode.IsDigit(rune(comment[1])) {
-		comment = comment[1:]
-	}
-
-	// special-case "NA", version found in final Emoji 11
-	if len(comment) > 3 && bytes.Equal(comment[:3], versionNAPrefix) {
-		comment = comment[2:]
+		return nil // weird, but possible
 	}
+	return parseComment(string(comment), out, strict)
+}
 
-	// find version
-	cand := numberPrefixOf(comment)
-	if len(cand) == 0 && comment[0] == '(' {
-		cand = numberPrefixOf(comment[1:])
-		if len(cand) != 0 && len(comment) > len(cand)+2 && comment[len(cand)+1] == ')' {
-			// ok
-		} else {
-			cand = nil
-		}
-	}
-	if len(cand) >= 2 { // look for "3.0", not "3"
-		v64, err := strconv.ParseFloat(string(cand), 32)
-		if err != nil {
-			return out, err
-		}
-		out.Version = float32(v64)
-	}
+// parseComment parses the trailing comment of a line containing emoji. This starts with any of a
+// version, count and glyphs (in any order), and finishes with notes. Glyphs in parentheses always
+// end this prefix, so notes after them are kept as-is. All string fields are sliced from the
+// single passed comment.
+func parseComment(comment string, out *Line, strict bool) error {
+	var glyphs string
+	var hasGlyphs bool
 
-	// work out whether we have a ( before any real chars
-	// this allows us to have notes like "AB button (blood type)"
-	var hasEarlyBracket bool
-	for _, v := range comment {
-		if v == '(' {
-			hasEarlyBracket = true
+loop:
+	for {
+		comment = strings.TrimLeft(comment, " \t")
+		if len(comment) == 0 {
 			break
 		}
-		if isASCIILetter(rune(v)) {
-			break
+		token := comment
+		if index := strings.IndexAny(comment, " \t"); index != -1 {
+			token = comment[:index]
 		}
-	}
 
-	// move to notes
-	notesFromIndex := bytes.IndexByte(comment, ')')
-	if !hasEarlyBracket || notesFromIndex == -1 {
-		// only happens in -test.txt, all other data has ()'s
-		spaceIndex := bytes.IndexByte(comment, ' ')
-		if spaceIndex != -1 {
-			notesFromIndex = spaceIndex
-		} else {
-			notesFromIndex = bytes.IndexFunc(comment, func(r rune) bool {
-				return !(r == 32 || r > 127) // skip over non-ascii and spaces
-			})
-		}
-	} else {
-		notesFromIndex++
-	}
-	notes := bytes.TrimSpace(comment[notesFromIndex:])
+		c := comment[0]
+		switch {
+		case c == '[':
+			// count e.g. "[3]"
+			end := strings.IndexByte(comment, ']')
+			if end == -1 {
+				break loop
+			}
+			count, err := strconv.Atoi(comment[1:end])
+			if err != nil {
+				break loop
+			}
+			out.Count = count
+			comment = comment[end+1:]
 
-	if len(notes) > 2 && notes[0] == 'E' {
-		cand = numberPrefixOf(notes[1:])
-		if len(cand) > 0 {
-			v64, err := strconv.ParseFloat(string(cand), 32)
+		case c == '(':
+			// glyphs e.g. "(🤼..🤾)", or version in older data e.g. "(1.1)"
+			end := strings.IndexByte(comment, ')')
+			if end == -1 || hasGlyphs {
+				break loop
+			}
+			inner := comment[1:end]
+			if cand := numberPrefixOf(inner); len(cand) == len(inner) && strings.Contains(cand, ".") {
+				v, err := ParseVersion(cand)
+				if err != nil {
+					if strict {
+						return err
+					}
+					break loop // keep the rest as notes when lenient
+				}
+				out.UnicodeVersion = v
+			} else if strings.IndexFunc(inner, isASCIILetter) != -1 {
+				break loop // notes like "(blood type)"
+			} else {
+				glyphs, hasGlyphs = inner, true
+			}
+			comment = comment[end+1:]
+			if hasGlyphs {
+				break loop
+			}
+
+		case !hasGlyphs && isGlyph(token):
+			// glyph in -test.txt e.g. "😎 smiling face"
+			glyphs, hasGlyphs = token, true
+			comment = comment[len(token):]
+
+		case token == "NA":
+			// special-case "NA", version found in final Emoji 11
+			out.Reserved = true
+			comment = comment[len(token):]
+
+		default:
+			// unicode version e.g. "6.0" or "V6.1", or emoji version e.g. "E0.6"
+			target := &out.UnicodeVersion
+			var prefix int
+			if c == 'V' || c == 'E' {
+				prefix = 1
+				if c == 'E' {
+					target = &out.EmojiVersion
+				}
+			}
+			cand := numberPrefixOf(comment[prefix:])
+			if !strings.Contains(cand, ".") { // look for "3.0", not "3"
+				if strict && prefix != 0 && cand != "" {
+					return fmt.Errorf("%w: %q", ErrInvalidVersion, token)
+				}
+				break loop
+			}
+			v, err := ParseVersion(cand)
 			if err != nil {
-				return out, err
+				if strict {
+					return err
+				}
+				break loop
+			}
+			*target = v
+			comment = comment[prefix+len(cand):]
+			if strict && comment != "" && !strings.ContainsRune(" \t[", rune(comment[0])) {
+				return fmt.Errorf("%w: %q", ErrInvalidVersion, token)
 			}
-			out.Version = float32(v64)
-			notes = bytes.TrimSpace(notes[len(cand)+1:])
 		}
 	}
 
-	out.Notes = string(notes)
-
-	return out, nil
+	out.Notes = strings.TrimSpace(comment)
+	out.NameLow = out.Notes
+	if out.High != 0 {
+		out.NameLow, out.NameHigh, _ = strings.Cut(out.Notes, string(rangeSep))
+		out.GlyphLow, out.GlyphHigh, _ = strings.Cut(glyphs, string(rangeSep))
+	} else {
+		out.GlyphLow = glyphs
+	}
+	return nil
 }
 
 func isASCIILetter(r rune) bool {
 	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
 }
 
-// numberPrefixOf returns the byte prefix which contains numeric or dot bytes.
-func numberPrefixOf(src []byte) []byte {
-	pastNumber := bytes.IndexFunc(src, func(r rune) bool {
+// isGlyph returns whether the passed token looks like rendered emoji, rather than a word.
+func isGlyph(token string) bool {
+	var hasEmoji bool
+	for _, r := range token {
+		if isASCIILetter(r) {
+			return false
+		}
+		hasEmoji = hasEmoji || r >= utf8.RuneSelf
+	}
+	return hasEmoji
+}
+
+// numberPrefixOf returns the prefix which contains numeric or dot bytes.
+func numberPrefixOf(src string) string {
+	pastNumber := strings.IndexFunc(src, func(r rune) bool {
 		return !unicode.IsNumber(r) && r != '.'
 	})
 	if pastNumber != -1 {
 		return src[:pastNumber]
 	}
-	return nil
+	return src
 }
 
-// parsePoint parses a hex Unicode code point, returning zero if invalid.
-func parsePoint(b []byte) rune {
-	point, err := strconv.ParseUint(string(b), 16, 32)
-	if err != nil {
-		return 0
+// unescape decodes all \x{HEX} escapes in src. Returns src unchanged if it has no escapes.
+func unescape(src []byte) ([]byte, error) {
+	index := bytes.Index(src, escapedPrefix)
+	if index == -1 {
+		return src, nil
+	}
+
+	out := make([]byte, 0, len(src))
+	for index != -1 {
+		out = append(out, src[:index]...)
+		src = src[index:]
+
+		end := bytes.IndexByte(src, '}')
+		if len(src) < 3 || src[2] != '{' || end == -1 {
+			return nil, fmt.Errorf("%w: %q", ErrUnhandledEscape, src)
+		}
+		point, err := strconv.ParseUint(string(src[3:end]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(point)) {
+			return nil, fmt.Errorf("%w: %q", ErrUnhandledEscape, src[:end+1])
+		}
+		out = utf8.AppendRune(out, rune(point))
+
+		src = src[end+1:]
+		index = bytes.Index(src, escapedPrefix)
 	}
-	return rune(point)
+	return append(out, src...), nil
+}
+
+// parsePoint parses a hex Unicode code point. If invalid, returns zero, or an error in strict mode.
+func parsePoint(b []byte, strict bool) (rune, error) {
+	b = bytes.TrimPrefix(b, pointPrefix) // e.g., "U+1F600" in emoji-ordering.txt
+	var point uint64
+	valid := len(b) != 0 && len(b) <= 8
+	for _, c := range b {
+		switch {
+		case c >= '0' && c <= '9':
+			point = point<<4 | uint64(c-'0')
+		case c >= 'a' && c <= 'f':
+			point = point<<4 | uint64(c-'a'+10)
+		case c >= 'A' && c <= 'F':
+			point = point<<4 | uint64(c-'A'+10)
+		default:
+			valid = false
+		}
+	}
+	if !valid {
+		if strict {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidPoint, b)
+		}
+		return 0, nil
+	}
+
+	if strict {
+		if point > unicode.MaxRune {
+			return 0, fmt.Errorf("%w: %s", ErrOutOfRange, b)
+		} else if point >= 0xd800 && point <= 0xdfff {
+			return 0, fmt.Errorf("%w: %s", ErrSurrogate, b)
+		}
+	}
+	return rune(point), nil
+}
+
+// cutField returns the first whitespace-separated field of b, and the remainder.
+func cutField(b []byte) (field, rest []byte) {
+	b = bytes.TrimLeft(b, " \t")
+	if index := bytes.IndexAny(b, " \t"); index != -1 {
+		return b[:index], bytes.TrimLeft(b[index:], " \t")
+	}
+	return b, nil
+}
+
+func hasString(all []string, s string) bool {
+	for _, v := range all {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
@@ -1,6 +1,7 @@
 package tr51
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -8,81 +9,194 @@ import (
 func TestParse(t *testing.T) {
 	testdata := map[string]Line{
 		// simple/comment cases
-		"":             Line{},
-		"# blah":       Line{Notes: "blah"},
-		"1F61F # blah": Line{Single: 0x1f61f, Notes: "blah"},
-		"# v1.0 blah":  Line{Notes: "v1.0 blah"},
+		"":             Line{Kind: LineBlank},
+		"# blah":       Line{Notes: "blah", Kind: LineComment},
+		"1F61F # blah": Line{Single: 0x1f61f, Notes: "blah", NameLow: "blah", Kind: LineData},
+		"# v1.0 blah":  Line{Notes: "v1.0 blah", Kind: LineComment},
 
 		// actual data
 		"1F61F ;	emoji ;	L1 ;	secondary ;	x	# V6.1 (😟) WORRIED FACE": Line{
-			Single:     0x1f61f,
-			Version:    6.1,
-			Notes:      "WORRIED FACE",
-			Properties: []string{"emoji", "L1", "secondary", "x"},
+			Single:         0x1f61f,
+			UnicodeVersion: Version{6, 1},
+			Notes:          "WORRIED FACE",
+			Properties:     []string{"emoji", "L1", "secondary", "x"},
+			GlyphLow:       "😟",
+			NameLow:        "WORRIED FACE",
+			Kind:           LineData,
 		},
 		"2194..2199    ; Emoji                #   [6] (↔️..↙️)  LEFT RIGHT ARROW..SOUTH WEST ARROW": Line{
 			Low:        0x2194,
 			High:       0x2199,
 			Notes:      "LEFT RIGHT ARROW..SOUTH WEST ARROW",
 			Properties: []string{"Emoji"},
+			Count:      6,
+			GlyphLow:   "↔️",
+			GlyphHigh:  "↙️",
+			NameLow:    "LEFT RIGHT ARROW",
+			NameHigh:   "SOUTH WEST ARROW",
+			Kind:       LineData,
+		},
+		"U+1F468 U+200D U+1F4BB ; 4.0 # 👨‍💻 man technologist": Line{
+			Sequence:   []rune{0x1f468, 0x200d, 0x1f4bb},
+			Notes:      "man technologist",
+			Properties: []string{"4.0"},
+			GlyphLow:   "👨‍💻",
+			NameLow:    "man technologist",
+			Kind:       LineData,
 		},
 		"002A FE0F 20E3; Emoji_Combining_Sequence  ; keycap: *                                                      # 3.0  [1] (*️⃣)": Line{
-			Sequence:   []rune{0x002a, 0xfe0f, 0x20e3},
-			Version:    3.0,
-			Properties: []string{"Emoji_Combining_Sequence", "keycap: *"},
+			Sequence:       []rune{0x002a, 0xfe0f, 0x20e3},
+			UnicodeVersion: Version{3, 0},
+			Properties:     []string{"Emoji_Combining_Sequence", "keycap: *"},
+			Count:          1,
+			GlyphLow:       "*️⃣",
+			Kind:           LineData,
 		},
 		"0023 FE0E  ; text style;  # (1.1) NUMBER SIGN": Line{
-			Sequence:   []rune{0x0023, 0xfe0e},
-			Version:    1.1,
-			Notes:      "NUMBER SIGN",
-			Properties: []string{"text style", ""},
+			Sequence:       []rune{0x0023, 0xfe0e},
+			UnicodeVersion: Version{1, 1},
+			Notes:          "NUMBER SIGN",
+			Properties:     []string{"text style", ""},
+			NameLow:        "NUMBER SIGN",
+			Kind:           LineData,
 		},
 		"1F649                                      ; fully-qualified     # 🙉 hear-no-evil monkey": Line{
 			Single:     0x1f649,
 			Notes:      "hear-no-evil monkey",
 			Properties: []string{"fully-qualified"},
+			GlyphLow:   "🙉",
+			NameLow:    "hear-no-evil monkey",
+			Kind:       LineData,
 		},
 		"1F442..1F4F7  ; Emoji_Presentation   #  6.0[182] (👂..📷)    ear..camera": Line{
-			Low:        0x1f442,
-			High:       0x1f4f7,
-			Version:    6.0,
-			Notes:      "ear..camera",
-			Properties: []string{"Emoji_Presentation"},
+			Low:            0x1f442,
+			High:           0x1f4f7,
+			UnicodeVersion: Version{6, 0},
+			Notes:          "ear..camera",
+			Properties:     []string{"Emoji_Presentation"},
+			Count:          182,
+			GlyphLow:       "👂",
+			GlyphHigh:      "📷",
+			NameLow:        "ear",
+			NameHigh:       "camera",
+			Kind:           LineData,
 		},
 		"0023 FE0F 20E3; Emoji_Combining_Sequence  ; keycap: #                                                      # 3.0  [1] (#️⃣)": Line{
-			Sequence:   []rune{0x0023, 0xfe0f, 0x20e3},
-			Version:    3.0,
-			Properties: []string{"Emoji_Combining_Sequence", "keycap: #"},
+			Sequence:       []rune{0x0023, 0xfe0f, 0x20e3},
+			UnicodeVersion: Version{3, 0},
+			Properties:     []string{"Emoji_Combining_Sequence", "keycap: #"},
+			Count:          1,
+			GlyphLow:       "#️⃣",
+			Kind:           LineData,
 		},
 		`0023 FE0F 20E3; Emoji_Keycap_Sequence     ; keycap: \x{23}                                                 #  3.0  [1] (#️⃣)`: Line{
-			Sequence:   []rune{0x0023, 0xfe0f, 0x20e3},
-			Version:    3.0,
-			Properties: []string{"Emoji_Keycap_Sequence", `keycap: #`},
+			Sequence:       []rune{0x0023, 0xfe0f, 0x20e3},
+			UnicodeVersion: Version{3, 0},
+			Properties:     []string{"Emoji_Keycap_Sequence", `keycap: #`},
+			Count:          1,
+			GlyphLow:       "#️⃣",
+			Kind:           LineData,
+		},
+		`002A FE0F 20E3; Emoji_Keycap_Sequence     ; keycap: \x{2A}                                                 #  3.0  [1] (*️⃣)`: Line{
+			Sequence:       []rune{0x002a, 0xfe0f, 0x20e3},
+			UnicodeVersion: Version{3, 0},
+			Properties:     []string{"Emoji_Keycap_Sequence", `keycap: *`},
+			Count:          1,
+			GlyphLow:       "*️⃣",
+			Kind:           LineData,
+		},
+		`0030 FE0F 20E3                             ; fully-qualified     # 0️⃣ keycap: \x{30}`: Line{
+			Sequence:   []rune{0x0030, 0xfe0f, 0x20e3},
+			Notes:      "keycap: 0",
+			Properties: []string{"fully-qualified"},
+			GlyphLow:   "0️⃣",
+			NameLow:    "keycap: 0",
+			Kind:       LineData,
 		},
 		`0031 FE0F 20E3                             ; fully-qualified     # 1️⃣ keycap: 1`: Line{
 			Sequence:   []rune{0x0031, 0xfe0f, 0x20e3},
 			Notes:      "keycap: 1",
 			Properties: []string{"fully-qualified"},
+			GlyphLow:   "1️⃣",
+			NameLow:    "keycap: 1",
+			Kind:       LineData,
 		},
 		`1F18E                                      ; fully-qualified     # 🆎 AB button (blood type)`: Line{
 			Single:     0x1f18e,
 			Notes:      "AB button (blood type)",
 			Properties: []string{"fully-qualified"},
+			GlyphLow:   "🆎",
+			NameLow:    "AB button (blood type)",
+			Kind:       LineData,
+		},
+		`1F93C..1F93E  ; Emoji                # E3.0   [3] (🤼..🤾)    people wrestling..person playing handball`: Line{
+			Low:          0x1f93c,
+			High:         0x1f93e,
+			EmojiVersion: Version{3, 0},
+			Notes:        "people wrestling..person playing handball",
+			Properties:   []string{"Emoji"},
+			Count:        3,
+			GlyphLow:     "🤼",
+			GlyphHigh:    "🤾",
+			NameLow:      "people wrestling",
+			NameHigh:     "person playing handball",
+			Kind:         LineData,
+		},
+		`1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face`: Line{
+			Single:       0x1f600,
+			EmojiVersion: Version{1, 0},
+			Notes:        "grinning face",
+			Properties:   []string{"fully-qualified"},
+			GlyphLow:     "😀",
+			NameLow:      "grinning face",
+			Kind:         LineData,
+		},
+		`1F947                                                  ; fully-qualified     # 🥇 E3.0 1st place medal`: Line{
+			Single:       0x1f947,
+			EmojiVersion: Version{3, 0},
+			Notes:        "1st place medal",
+			Properties:   []string{"fully-qualified"},
+			GlyphLow:     "🥇",
+			NameLow:      "1st place medal",
+			Kind:         LineData,
+		},
+		`263A          ; Emoji                # V1.1 E0.6   [1] (☺️)       smiling face`: Line{
+			Single:         0x263a,
+			UnicodeVersion: Version{1, 1},
+			EmojiVersion:   Version{0, 6},
+			Notes:          "smiling face",
+			Properties:     []string{"Emoji"},
+			Count:          1,
+			GlyphLow:       "☺️",
+			NameLow:        "smiling face",
+			Kind:           LineData,
 		},
 		`1F62C         ; Extended_Pictographic#  6.1  [1] (😬)       grimacing face`: Line{
-			Single:     0x1f62c,
-			Version:    6.1,
-			Notes:      "grimacing face",
-			Properties: []string{"Extended_Pictographic"},
+			Single:         0x1f62c,
+			UnicodeVersion: Version{6, 1},
+			Notes:          "grimacing face",
+			Properties:     []string{"Extended_Pictographic"},
+			Count:          1,
+			GlyphLow:       "😬",
+			NameLow:        "grimacing face",
+			Kind:           LineData,
 		},
 		`1F93F         ; Extended_Pictographic#   NA  [1] (🤿️)       <reserved-1F93F>`: Line{
 			Single:     0x1f93f,
+			Reserved:   true,
 			Notes:      "<reserved-1F93F>",
 			Properties: []string{"Extended_Pictographic"},
+			Count:      1,
+			GlyphLow:   "🤿️",
+			NameLow:    "<reserved-1F93F>",
+			Kind:       LineData,
 		},
 	}
 
 	for input, expected := range testdata {
+		for _, p := range expected.Properties {
+			expected.Props = expected.Props.With(Property(p))
+		}
 		actual, err := Parse([]byte(input))
 		if err != nil {
 			t.Errorf("got err: %v", err)
@@ -93,6 +207,23 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseEscape(t *testing.T) {
+	bad := []string{
+		`0023 FE0F 20E3; Emoji_Keycap_Sequence ; keycap: \x23`,
+		`0023 FE0F 20E3; Emoji_Keycap_Sequence ; keycap: \x{23`,
+		`0023 FE0F 20E3; Emoji_Keycap_Sequence ; keycap: \x{}`,
+		`0023 FE0F 20E3; Emoji_Keycap_Sequence ; keycap: \x{ZZ}`,
+		`0023 FE0F 20E3; Emoji_Keycap_Sequence ; keycap: \x{D800}`,
+		`0023 FE0F 20E3 ; fully-qualified # #️⃣ keycap: \x{110000}`,
+	}
+	for _, input := range bad {
+		_, err := Parse([]byte(input))
+		if !errors.Is(err, ErrUnhandledEscape) {
+			t.Errorf("expected ErrUnhandledEscape for %s, was %v", input, err)
+		}
+	}
+}
+
 func TestHasEmoji(t *testing.T) {
 	yes := "1F61F ;	emoji ;	L1 ;	secondary ;	x	# V6.1 (😟) WORRIED FACE"
 	if actual, err := Parse([]byte(yes)); err != nil || !actual.HasEmoji() {
@@ -103,3 +234,23 @@ func TestHasEmoji(t *testing.T) {
 		t.Errorf("comment had err or had emoji: %v", err)
 	}
 }
+
+func TestEffectiveVersion(t *testing.T) {
+	tests := map[string]Version{
+		"1F600 ; fully-qualified # 😀 E1.0 grinning face":     {Major: 1},
+		"231A..231B    ; Emoji  #  1.1  [2] (⌚..⌛)    watch": {Major: 1, Minor: 1},
+		"1F61F ; emoji # V6.1 E0.6 (😟) WORRIED FACE":         {Major: 0, Minor: 6},
+		"1F600 ; fully-qualified # 😀 grinning face":          {},
+	}
+
+	for raw, expected := range tests {
+		l, err := Parse([]byte(raw))
+		if err != nil {
+			t.Errorf("got err on %q: %v", raw, err)
+			continue
+		}
+		if actual := l.EffectiveVersion(); actual != expected {
+			t.Errorf("expected %v, was %v", expected, actual)
+		}
+	}
+}
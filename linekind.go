@@ -0,0 +1,57 @@
+package tr51
+
+import "strings"
+
+// LineKind is the kind of a single line of TR51 data. This is only set by Parse and readers, so is
+// LineUnknown for a Line built by hand.
+type LineKind int
+
+const (
+	LineUnknown  LineKind = iota // not classified
+	LineBlank                    // no data or notes, e.g., "#"
+	LineData                     // emoji data, possibly with a trailing comment
+	LineComment                  // free comment
+	LineGroup                    // comment starting a group, e.g., "# group: Smileys & Emotion"
+	LineSubgroup                 // comment starting a subgroup, e.g., "# subgroup: face-smiling"
+	LineHeader                   // comment before the first data line, if read by Reader
+)
+
+var lineKindNames = []string{
+	LineUnknown:  "unknown",
+	LineBlank:    "blank",
+	LineData:     "data",
+	LineComment:  "comment",
+	LineGroup:    "group",
+	LineSubgroup: "subgroup",
+	LineHeader:   "header",
+}
+
+func (k LineKind) String() string {
+	if k < 0 || int(k) >= len(lineKindNames) {
+		return lineKindNames[LineUnknown]
+	}
+	return lineKindNames[k]
+}
+
+const (
+	groupPrefix    = "group: "
+	subgroupPrefix = "subgroup: "
+)
+
+// classify sets the kind of this line, and its group or subgroup if it starts one. This doesn't
+// know about context, so never returns LineHeader.
+func (lp *Line) classify() {
+	if lp.HasEmoji() {
+		lp.Kind = LineData
+	} else if group, ok := strings.CutPrefix(lp.Notes, groupPrefix); ok {
+		lp.Kind = LineGroup
+		lp.Group = group
+	} else if subgroup, ok := strings.CutPrefix(lp.Notes, subgroupPrefix); ok {
+		lp.Kind = LineSubgroup
+		lp.Subgroup = subgroup
+	} else if lp.Notes != "" || len(lp.Properties) != 0 {
+		lp.Kind = LineComment
+	} else {
+		lp.Kind = LineBlank
+	}
+}
@@ -0,0 +1,59 @@
+package tr51
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReaderLineKind(t *testing.T) {
+	raw := `# emoji-test.txt
+# Version: 15.1
+#
+# group: Smileys & Emotion
+
+# subgroup: face-smiling
+1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face
+
+# subgroup: face-affection
+1F970                                                  ; fully-qualified     # 🥰 E11.0 smiling face with hearts
+# a comment
+# group: People & Body
+1F44B                                                  ; fully-qualified     # 👋 E0.6 waving hand
+`
+
+	type expectedLine struct {
+		kind            LineKind
+		group, subgroup string
+	}
+	smileys := "Smileys & Emotion"
+	expected := []expectedLine{
+		{LineHeader, "", ""},
+		{LineHeader, "", ""},
+		{LineBlank, "", ""},
+		{LineGroup, smileys, ""},
+		{LineSubgroup, smileys, "face-smiling"},
+		{LineData, smileys, "face-smiling"},
+		{LineSubgroup, smileys, "face-affection"},
+		{LineData, smileys, "face-affection"},
+		{LineComment, smileys, "face-affection"},
+		{LineGroup, "People & Body", ""},
+		{LineData, "People & Body", ""},
+	}
+
+	var actual []expectedLine
+	for l, err := range NewReader(strings.NewReader(raw)).All() {
+		if err != nil {
+			t.Fatalf("got err: %v", err)
+		}
+		actual = append(actual, expectedLine{l.Kind, l.Group, l.Subgroup})
+	}
+
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d lines, was %d: %+v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("line %d: expected %+v, was %+v", i, expected[i], actual[i])
+		}
+	}
+}
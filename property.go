@@ -0,0 +1,126 @@
+package tr51
+
+// Property is a property, type field or status value found in TR51 data.
+type Property string
+
+const (
+	// emoji-data.txt
+	PropertyEmoji                Property = "Emoji"
+	PropertyEmojiPresentation    Property = "Emoji_Presentation"
+	PropertyEmojiModifier        Property = "Emoji_Modifier"
+	PropertyEmojiModifierBase    Property = "Emoji_Modifier_Base"
+	PropertyEmojiComponent       Property = "Emoji_Component"
+	PropertyExtendedPictographic Property = "Extended_Pictographic"
+
+	// emoji-sequences.txt and emoji-zwj-sequences.txt
+	PropertyBasicEmoji               Property = "Basic_Emoji"
+	PropertyEmojiKeycapSequence      Property = "Emoji_Keycap_Sequence"
+	PropertyRGIEmojiFlagSequence     Property = "RGI_Emoji_Flag_Sequence"
+	PropertyRGIEmojiTagSequence      Property = "RGI_Emoji_Tag_Sequence"
+	PropertyRGIEmojiModifierSequence Property = "RGI_Emoji_Modifier_Sequence"
+	PropertyRGIEmojiZWJSequence      Property = "RGI_Emoji_ZWJ_Sequence"
+
+	// emoji-sequences.txt and emoji-zwj-sequences.txt, before Emoji 13.0
+	PropertyEmojiCombiningSequence Property = "Emoji_Combining_Sequence"
+	PropertyEmojiFlagSequence      Property = "Emoji_Flag_Sequence"
+	PropertyEmojiTagSequence       Property = "Emoji_Tag_Sequence"
+	PropertyEmojiModifierSequence  Property = "Emoji_Modifier_Sequence"
+	PropertyEmojiZWJSequence       Property = "Emoji_ZWJ_Sequence"
+
+	// emoji-test.txt
+	PropertyFullyQualified     Property = "fully-qualified"
+	PropertyMinimallyQualified Property = "minimally-qualified"
+	PropertyUnqualified        Property = "unqualified"
+	PropertyNonFullyQualified  Property = "non-fully-qualified" // before Emoji 12.0
+	PropertyComponent          Property = "component"
+
+	// emoji-variation-sequences.txt
+	PropertyTextStyle  Property = "text style"
+	PropertyEmojiStyle Property = "emoji style"
+
+	// emoji-data.txt from Emoji 1.0: default style, level and modifier status
+	PropertyDefaultEmoji      Property = "emoji"
+	PropertyDefaultText       Property = "text"
+	PropertyLevel1            Property = "L1"
+	PropertyLevel2            Property = "L2"
+	PropertyLevelNA           Property = "NA"
+	PropertyModifierPrimary   Property = "primary"
+	PropertyModifierSecondary Property = "secondary"
+	PropertyModifierNone      Property = "none"
+)
+
+// allProperties contains every known Property, in PropertySet bit order.
+var allProperties = []Property{
+	PropertyEmoji, PropertyEmojiPresentation, PropertyEmojiModifier, PropertyEmojiModifierBase,
+	PropertyEmojiComponent, PropertyExtendedPictographic,
+	PropertyBasicEmoji, PropertyEmojiKeycapSequence, PropertyRGIEmojiFlagSequence,
+	PropertyRGIEmojiTagSequence, PropertyRGIEmojiModifierSequence, PropertyRGIEmojiZWJSequence,
+	PropertyEmojiCombiningSequence, PropertyEmojiFlagSequence, PropertyEmojiTagSequence,
+	PropertyEmojiModifierSequence, PropertyEmojiZWJSequence,
+	PropertyFullyQualified, PropertyMinimallyQualified, PropertyUnqualified,
+	PropertyNonFullyQualified, PropertyComponent,
+	PropertyTextStyle, PropertyEmojiStyle,
+	PropertyDefaultEmoji, PropertyDefaultText, PropertyLevel1, PropertyLevel2, PropertyLevelNA,
+	PropertyModifierPrimary, PropertyModifierSecondary, PropertyModifierNone,
+}
+
+// knownProperties maps the raw value of every known Property to its string and bit, so parsing
+// them doesn't allocate.
+var knownProperties = makeKnownProperties(allProperties)
+
+type knownProperty struct {
+	s   string
+	bit PropertySet
+}
+
+func makeKnownProperties(all []Property) map[string]knownProperty {
+	out := make(map[string]knownProperty, len(all))
+	for i, p := range all {
+		out[string(p)] = knownProperty{s: string(p), bit: 1 << i}
+	}
+	return out
+}
+
+// Known returns whether this is a Property found in TR51 data published by Unicode.
+func (p Property) Known() bool {
+	_, ok := knownProperties[string(p)]
+	return ok
+}
+
+func (p Property) String() string {
+	return string(p)
+}
+
+// PropertySet is a set of known properties. Unknown properties cannot be stored.
+type PropertySet uint64
+
+// Has returns whether this set contains the given Property.
+func (s PropertySet) Has(p Property) bool {
+	k, ok := knownProperties[string(p)]
+	return ok && s&k.bit != 0
+}
+
+// With returns this set with the given Property added, if it is known.
+func (s PropertySet) With(p Property) PropertySet {
+	return s | knownProperties[string(p)].bit
+}
+
+// Properties returns the properties in this set, in a stable order.
+func (s PropertySet) Properties() []Property {
+	var out []Property
+	for i, p := range allProperties {
+		if s&(1<<i) != 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// internProperty returns a string for the passed property, allocating only if it is unknown,
+// along with its bit if known.
+func internProperty(b []byte) (string, PropertySet) {
+	if k, ok := knownProperties[string(b)]; ok {
+		return k.s, k.bit
+	}
+	return string(b), 0
+}
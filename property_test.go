@@ -0,0 +1,78 @@
+package tr51
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPropertySet(t *testing.T) {
+	if len(allProperties) > 64 {
+		t.Fatalf("expected at most 64 properties, was %d", len(allProperties))
+	}
+
+	l, err := Parse([]byte("1F3FB..1F3FF ; Emoji_Modifier ; Emoji_Component ; custom # 8.0 [5]"))
+	if err != nil {
+		t.Fatalf("got err: %v", err)
+	}
+
+	expected := []Property{PropertyEmojiModifier, PropertyEmojiComponent}
+	if actual := l.Props.Properties(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, was %v", expected, actual)
+	}
+
+	checks := map[Property]bool{
+		PropertyEmojiModifier:     true,
+		PropertyEmojiComponent:    true,
+		PropertyEmojiPresentation: false,
+		"custom":                  true,
+		"Emoji_Modifer":           false,
+	}
+	for p, expected := range checks {
+		if actual := l.HasProperty(p); actual != expected {
+			t.Errorf("%q: expected %v, was %v", p, expected, actual)
+		}
+	}
+
+	if actual := PropertySet(0).With("custom"); actual != 0 {
+		t.Errorf("expected unknown property to be ignored, was %v", actual)
+	}
+}
+
+func TestReaderUnknownProperty(t *testing.T) {
+	const raw = `1F600 ; Emoji # 6.1 [1] (😀) grinning face
+1F601 ; Emoij # 6.1 [1] (😁) beaming face with smiling eyes
+`
+
+	var unknown []string
+	r := NewReaderWithOptions(strings.NewReader(raw), ReaderOpts{
+		UnknownProperty: func(p string) error {
+			unknown = append(unknown, p)
+			return nil
+		},
+	})
+	for _, err := range r.All() {
+		if err != nil {
+			t.Errorf("got err: %v", err)
+		}
+	}
+	if expected := []string{"Emoij"}; !reflect.DeepEqual(unknown, expected) {
+		t.Errorf("expected %v, was %v", expected, unknown)
+	}
+
+	errTypo := errors.New("typo")
+	r = NewReaderWithOptions(strings.NewReader(raw), ReaderOpts{
+		UnknownProperty: func(p string) error { return errTypo },
+	})
+	var err error
+	for _, err = range r.All() {
+		if err != nil {
+			break
+		}
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, errTypo) || pe.Line != 2 {
+		t.Errorf("expected ParseError on line 2 wrapping hook error, was %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package tr51
+
+import (
+	"slices"
+	"sort"
+	"unicode"
+)
+
+// RuneRange is an inclusive range of runes.
+type RuneRange struct {
+	Low, High rune
+}
+
+// RuneSet is a set of runes, stored as sorted and coalesced ranges. The zero value is empty.
+type RuneSet struct {
+	ranges []RuneRange // non-overlapping and non-adjacent
+}
+
+// Add adds the inclusive range of runes to this set. Reversed ranges are ignored.
+func (s *RuneSet) Add(low, high rune) {
+	if low > high {
+		return
+	}
+
+	// fast path for appending, as TR51 data is mostly in order
+	n := len(s.ranges)
+	if n == 0 || low > s.ranges[n-1].High+1 {
+		s.ranges = append(s.ranges, RuneRange{low, high})
+		return
+	}
+
+	// find ranges [i,j) which overlap or are adjacent to the new range, and replace them
+	i := sort.Search(n, func(i int) bool { return s.ranges[i].High+1 >= low })
+	j := sort.Search(n, func(i int) bool { return s.ranges[i].Low-1 > high })
+	if i < j {
+		low = min(low, s.ranges[i].Low)
+		high = max(high, s.ranges[j-1].High)
+	}
+	s.ranges = slices.Replace(s.ranges, i, j, RuneRange{low, high})
+}
+
+// AddLine adds the single rune or range of runes from the passed Line. Sequences are ignored, as
+// they don't represent individual runes.
+func (s *RuneSet) AddLine(l Line) {
+	if l.Single != 0 || l.High != 0 {
+		s.Add(l.AsRange())
+	}
+}
+
+// Contains returns whether the passed rune is in this set.
+func (s RuneSet) Contains(r rune) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].High >= r })
+	return i < len(s.ranges) && s.ranges[i].Low <= r
+}
+
+// Len returns the number of runes in this set.
+func (s RuneSet) Len() int {
+	var out int
+	for _, rr := range s.ranges {
+		out += int(rr.High-rr.Low) + 1
+	}
+	return out
+}
+
+// Ranges returns the sorted, coalesced ranges in this set.
+func (s RuneSet) Ranges() []RuneRange {
+	return slices.Clone(s.ranges)
+}
+
+// Clone returns a copy of this set which can be modified independently.
+func (s RuneSet) Clone() RuneSet {
+	return RuneSet{ranges: slices.Clone(s.ranges)}
+}
+
+// Union returns a new set containing runes in either set.
+func (s RuneSet) Union(o RuneSet) RuneSet {
+	out := s.Clone()
+	for _, rr := range o.ranges {
+		out.Add(rr.Low, rr.High)
+	}
+	return out
+}
+
+// Intersection returns a new set containing runes in both sets.
+func (s RuneSet) Intersection(o RuneSet) RuneSet {
+	var out RuneSet
+	a, b := s.ranges, o.ranges
+	for len(a) != 0 && len(b) != 0 {
+		low, high := max(a[0].Low, b[0].Low), min(a[0].High, b[0].High)
+		if low <= high {
+			out.ranges = append(out.ranges, RuneRange{low, high})
+		}
+		if a[0].High < b[0].High {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return out
+}
+
+// Difference returns a new set containing runes in this set but not the other.
+func (s RuneSet) Difference(o RuneSet) RuneSet {
+	var out RuneSet
+	b := o.ranges
+	for _, rr := range s.ranges {
+		low := rr.Low
+		for len(b) != 0 && b[0].High < low {
+			b = b[1:]
+		}
+		for _, cut := range b {
+			if cut.Low > rr.High {
+				break
+			}
+			if cut.Low > low {
+				out.ranges = append(out.ranges, RuneRange{low, cut.Low - 1})
+			}
+			low = cut.High + 1
+		}
+		if low <= rr.High {
+			out.ranges = append(out.ranges, RuneRange{low, rr.High})
+		}
+	}
+	return out
+}
+
+// RangeTable returns this set as a table for use with unicode.Is.
+func (s RuneSet) RangeTable() *unicode.RangeTable {
+	out := &unicode.RangeTable{}
+	for _, rr := range s.ranges {
+		if rr.Low <= 0xffff {
+			high := min(rr.High, 0xffff)
+			out.R16 = append(out.R16, unicode.Range16{Lo: uint16(rr.Low), Hi: uint16(high), Stride: 1})
+			if high <= unicode.MaxLatin1 {
+				out.LatinOffset++
+			}
+			if rr.High <= 0xffff {
+				continue
+			}
+			rr.Low = 0x10000
+		}
+		out.R32 = append(out.R32, unicode.Range32{Lo: uint32(rr.Low), Hi: uint32(rr.High), Stride: 1})
+	}
+	return out
+}
+
+// RuneSetFromTable returns a set containing all runes in the passed table.
+func RuneSetFromTable(t *unicode.RangeTable) RuneSet {
+	var s RuneSet
+	for _, r := range t.R16 {
+		addStride(&s, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range t.R32 {
+		addStride(&s, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	return s
+}
+
+func addStride(s *RuneSet, low, high, stride rune) {
+	if stride <= 1 {
+		s.Add(low, high)
+		return
+	}
+	for r := low; r <= high; r += stride {
+		s.Add(r, r)
+	}
+}
@@ -0,0 +1,111 @@
+package tr51
+
+import (
+	"reflect"
+	"testing"
+	"unicode"
+)
+
+func makeRuneSet(ranges ...RuneRange) RuneSet {
+	var s RuneSet
+	for _, rr := range ranges {
+		s.Add(rr.Low, rr.High)
+	}
+	return s
+}
+
+func TestRuneSet(t *testing.T) {
+	type testData struct {
+		in  []RuneRange
+		out []RuneRange
+	}
+	data := []testData{
+		{nil, nil},
+		{[]RuneRange{{5, 1}}, nil},
+		{[]RuneRange{{1, 3}, {4, 6}}, []RuneRange{{1, 6}}},
+		{[]RuneRange{{10, 12}, {1, 3}}, []RuneRange{{1, 3}, {10, 12}}},
+		{[]RuneRange{{1, 3}, {10, 12}, {20, 22}, {2, 11}}, []RuneRange{{1, 12}, {20, 22}}},
+		{[]RuneRange{{1, 3}, {10, 12}, {5, 5}}, []RuneRange{{1, 3}, {5, 5}, {10, 12}}},
+		{[]RuneRange{{1, 3}, {10, 12}, {4, 9}}, []RuneRange{{1, 12}}},
+		{[]RuneRange{{2, 3}, {0, 100}}, []RuneRange{{0, 100}}},
+	}
+	for _, td := range data {
+		actual := makeRuneSet(td.in...).Ranges()
+		if !reflect.DeepEqual(actual, td.out) {
+			t.Errorf("for %v, expected %v, was %v", td.in, td.out, actual)
+		}
+	}
+
+	s := makeRuneSet(RuneRange{0x2194, 0x2199}, RuneRange{0x1f600, 0x1f600})
+	checks := map[rune]bool{0x2193: false, 0x2194: true, 0x2199: true, 0x219a: false, 0x1f600: true}
+	for r, expected := range checks {
+		if actual := s.Contains(r); actual != expected {
+			t.Errorf("%U: expected %v, was %v", r, expected, actual)
+		}
+	}
+	if s.Len() != 7 {
+		t.Errorf("expected 7 runes, was %d", s.Len())
+	}
+}
+
+func TestRuneSetOps(t *testing.T) {
+	a := makeRuneSet(RuneRange{1, 10}, RuneRange{20, 30})
+	b := makeRuneSet(RuneRange{5, 22}, RuneRange{25, 26}, RuneRange{40, 41})
+
+	union := []RuneRange{{1, 30}, {40, 41}}
+	if actual := a.Union(b).Ranges(); !reflect.DeepEqual(actual, union) {
+		t.Errorf("union: expected %v, was %v", union, actual)
+	}
+
+	intersection := []RuneRange{{5, 10}, {20, 22}, {25, 26}}
+	if actual := a.Intersection(b).Ranges(); !reflect.DeepEqual(actual, intersection) {
+		t.Errorf("intersection: expected %v, was %v", intersection, actual)
+	}
+
+	difference := []RuneRange{{1, 4}, {23, 24}, {27, 30}}
+	if actual := a.Difference(b).Ranges(); !reflect.DeepEqual(actual, difference) {
+		t.Errorf("difference: expected %v, was %v", difference, actual)
+	}
+
+	if actual := b.Difference(a).Ranges(); !reflect.DeepEqual(actual, []RuneRange{{11, 19}, {40, 41}}) {
+		t.Errorf("reverse difference: was %v", actual)
+	}
+
+	// operations must not modify their inputs
+	if actual := a.Ranges(); !reflect.DeepEqual(actual, []RuneRange{{1, 10}, {20, 30}}) {
+		t.Errorf("expected input to be unchanged, was %v", actual)
+	}
+}
+
+func TestRuneSetRangeTable(t *testing.T) {
+	var s RuneSet
+	for _, raw := range []string{
+		"00A9          ; Emoji                #  1.1  [1] (©️)       copyright",
+		"FFF0..10002   ; Emoji                #  1.1  [3] (x..y)     test",
+		"1F600         ; Emoji                #  6.1  [1] (😀)       grinning face",
+		"0023 FE0F 20E3; Emoji_Keycap_Sequence ; keycap: \\x{23}   # E0.6   [1] (#️⃣)",
+	} {
+		l, err := Parse([]byte(raw))
+		if err != nil {
+			t.Fatalf("got err: %v", err)
+		}
+		s.AddLine(l)
+	}
+
+	table := s.RangeTable()
+	if table.LatinOffset != 1 || len(table.R16) != 2 || len(table.R32) != 2 {
+		t.Errorf("unexpected table: %+v", table)
+	}
+	for r := rune(0); r <= 0x1ffff; r++ {
+		if unicode.Is(table, r) != s.Contains(r) {
+			t.Errorf("%U: expected %v", r, s.Contains(r))
+		}
+	}
+
+	if actual := RuneSetFromTable(table); !reflect.DeepEqual(actual, s) {
+		t.Errorf("expected %v, was %v", s.Ranges(), actual.Ranges())
+	}
+	if actual := RuneSetFromTable(unicode.Hex_Digit).Len(); actual != 44 {
+		t.Errorf("expected 44 hex digits, was %d", actual)
+	}
+}
@@ -0,0 +1,138 @@
+package tr51
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Scanner reads TR51 data line-by-line like Reader, but reuses a single Line to allocate less.
+// Properties which are well-known are not allocated at all, but comments are still allocated as
+// strings.
+type Scanner struct {
+	r      *bufio.Reader
+	opts   ReaderOpts
+	number int // number of the last line read
+	buf    []byte
+	line   Line
+	err    error
+
+	header   Header
+	data     bool // whether a line with emoji has been read
+	group    string
+	subgroup string
+}
+
+// NewScanner returns a new Scanner for TR51 data.
+func NewScanner(r io.Reader) *Scanner {
+	return NewScannerWithOptions(r, ReaderOpts{})
+}
+
+// NewScannerWithOptions returns a new Scanner for TR51 data with the given options.
+func NewScannerWithOptions(r io.Reader, opts ReaderOpts) *Scanner {
+	return &Scanner{r: bufio.NewReader(r), opts: opts}
+}
+
+// Scan advances to the next non-empty line, which is then available via Line. Returns false when
+// there are no more lines, or on error.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+
+	for {
+		raw, err := s.readLine()
+		if err != nil {
+			s.err = err
+			return false
+		}
+		trimmed := bytes.TrimSpace(raw)
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		err = parseInto(trimmed, &s.line, s.opts.Strict)
+		if err == nil {
+			err = s.checkProperty()
+		}
+		if err != nil {
+			s.err = &ParseError{
+				Line: s.number,
+				Raw:  append([]byte(nil), raw...),
+				Err:  err,
+			}
+			return false
+		}
+		s.line.LineNumber = s.number
+		s.line.classify()
+
+		switch s.line.Kind {
+		case LineGroup:
+			s.group, s.subgroup = s.line.Group, ""
+		case LineSubgroup:
+			s.subgroup = s.line.Subgroup
+		}
+		s.line.Group, s.line.Subgroup = s.group, s.subgroup
+
+		if !s.data {
+			if s.line.Kind == LineData {
+				s.data = true
+			} else {
+				s.header.parse(s.line.Notes)
+				if s.line.Kind == LineComment {
+					s.line.Kind = LineHeader
+				}
+			}
+		}
+		return true
+	}
+}
+
+// checkProperty calls the UnknownProperty hook, if any, for an unknown first property.
+func (s *Scanner) checkProperty() error {
+	if s.opts.UnknownProperty == nil || len(s.line.Properties) == 0 {
+		return nil
+	}
+	p := s.line.Properties[0]
+	if s.line.Props.Has(Property(p)) {
+		return nil
+	}
+	return s.opts.UnknownProperty(p)
+}
+
+// Line returns the most recent line found by Scan. It, and its slices, are only valid until the
+// next call to Scan.
+func (s *Scanner) Line() *Line {
+	return &s.line
+}
+
+// Err returns the first non-EOF error found by Scan.
+func (s *Scanner) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// readLine returns the next whole line, which is only valid until the next call.
+func (s *Scanner) readLine() ([]byte, error) {
+	raw, isPrefix, err := s.r.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	s.number++
+	if !isPrefix {
+		return raw, nil
+	}
+
+	// line is longer than the buffer, so copy and continue
+	s.buf = append(s.buf[:0], raw...)
+	for isPrefix {
+		raw, isPrefix, err = s.r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		s.buf = append(s.buf, raw...)
+	}
+	return s.buf, nil
+}
@@ -0,0 +1,145 @@
+package tr51
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const benchmarkData = `# emoji-test.txt
+# Version: 15.1
+
+# group: Smileys & Emotion
+
+# subgroup: face-smiling
+1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face
+1F603                                                  ; fully-qualified     # 😃 E0.6 grinning face with big eyes
+263A FE0F                                              ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                                   ; unqualified         # ☺ E0.6 smiling face
+1F9D1 1F3FC 200D 1F91D 200D 1F9D1 1F3FB                ; fully-qualified     # 🧑🏼‍🤝‍🧑🏻 E12.0 people holding hands: medium-light skin tone, light skin tone
+0023 FE0F 20E3                                         ; fully-qualified     # #️⃣ E0.6 keycap: #
+1F93C..1F93E  ; Emoji                # E3.0   [3] (🤼..🤾)    people wrestling..person playing handball
+1F442..1F4F7  ; Emoji_Presentation   #  6.0[182] (👂..📷)    ear..camera
+`
+
+func TestScanner(t *testing.T) {
+	var expected []Line
+	ReadFunc(strings.NewReader(benchmarkData), func(l Line) error {
+		expected = append(expected, l)
+		return nil
+	})
+
+	s := NewScanner(strings.NewReader(benchmarkData))
+	var i int
+	for s.Scan() {
+		actual := s.Line()
+		if i >= len(expected) {
+			t.Fatalf("got too many lines: %+v", actual)
+		}
+		e := expected[i]
+		if actual.AsString() != e.AsString() || actual.Notes != e.Notes || actual.LineNumber != e.LineNumber {
+			t.Errorf("expected %+v, was %+v", e, actual)
+		}
+		if len(actual.Properties) != 0 && !reflect.DeepEqual(actual.Properties, e.Properties) {
+			t.Errorf("expected properties %v, was %v", e.Properties, actual.Properties)
+		}
+		i++
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("got err: %v", err)
+	}
+	if i != len(expected) {
+		t.Errorf("expected %d lines, was %d", len(expected), i)
+	}
+
+	s = NewScanner(strings.NewReader("ZZZZ..YYYY..XXXX ; Emoji"))
+	if s.Scan() {
+		t.Errorf("expected Scan to fail on invalid range")
+	}
+	if _, ok := s.Err().(*ParseError); !ok {
+		t.Errorf("expected ParseError, was %v", s.Err())
+	}
+}
+
+func TestEachShared(t *testing.T) {
+	l, _ := Parse([]byte("1F93C..1F93E  ; Emoji"))
+
+	var expected, actual []string
+	l.Each(func(r []rune) {
+		expected = append(expected, string(r))
+	})
+	l.EachShared(func(r []rune) {
+		actual = append(actual, string(r))
+	})
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, was %v", expected, actual)
+	}
+}
+
+func benchmarkInput(b *testing.B) *bytes.Reader {
+	b.Helper()
+	b.ReportAllocs()
+	raw := strings.Repeat(benchmarkData, 100)
+	b.SetBytes(int64(len(raw)))
+	return bytes.NewReader([]byte(raw))
+}
+
+// BenchmarkParse reads lines like the original Reader did, parsing each one separately.
+func BenchmarkParse(b *testing.B) {
+	input := benchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		input.Seek(0, io.SeekStart)
+		r := bufio.NewReader(input)
+		for {
+			raw, _, err := r.ReadLine()
+			if err != nil {
+				break
+			}
+			if raw = bytes.TrimSpace(raw); len(raw) > 0 {
+				Parse(raw)
+			}
+		}
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	input := benchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		input.Seek(0, io.SeekStart)
+		r := NewReader(input)
+		for {
+			if _, err := r.Read(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkScanner(b *testing.B) {
+	input := benchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		input.Seek(0, io.SeekStart)
+		s := NewScanner(input)
+		for s.Scan() {
+		}
+	}
+}
+
+func BenchmarkEach(b *testing.B) {
+	l, _ := Parse([]byte("1F442..1F4F7  ; Emoji_Presentation   #  6.0[182] (👂..📷)    ear..camera"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Each(func([]rune) {})
+	}
+}
+
+func BenchmarkEachShared(b *testing.B) {
+	l, _ := Parse([]byte("1F442..1F4F7  ; Emoji_Presentation   #  6.0[182] (👂..📷)    ear..camera"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.EachShared(func([]rune) {})
+	}
+}
@@ -0,0 +1,197 @@
+// Package main of tablegen generates Go source containing emoji.Tables, so TR51 data can be used
+// without any runtime I/O.
+//
+// It reads emoji-data.txt, emoji-test.txt, emoji-sequences.txt and emoji-zwj-sequences.txt from
+// the current directory, skipping any which are missing, and writes Go source to stdout.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/samthor/tr51"
+	"github.com/samthor/tr51/emoji"
+)
+
+var (
+	flagPackage = flag.String("package", "emojitables", "package name of generated source")
+)
+
+func main() {
+	flag.Parse()
+
+	var data *emoji.Data
+	if r := readTR51("emoji-data.txt"); r != nil {
+		var err error
+		if data, err = emoji.NewData(r); err != nil {
+			log.Fatalf("could not parse emoji-data.txt: %v", err)
+		}
+	}
+
+	var test *emoji.Test
+	if r := readTR51("emoji-test.txt"); r != nil {
+		var err error
+		if test, err = emoji.NewTest(r); err != nil {
+			log.Fatalf("could not parse emoji-test.txt: %v", err)
+		}
+	}
+
+	var sequences *emoji.Sequences
+	var readers []*tr51.Reader
+	for _, filename := range []string{"emoji-sequences.txt", "emoji-zwj-sequences.txt"} {
+		if r := readTR51(filename); r != nil {
+			readers = append(readers, r)
+		}
+	}
+	if len(readers) != 0 {
+		var err error
+		if sequences, err = emoji.NewSequences(readers...); err != nil {
+			log.Fatalf("could not parse sequences: %v", err)
+		}
+	}
+
+	tables := emoji.NewTables(data, test, sequences)
+	log.Printf("properties: %d", len(tables.Properties))
+	log.Printf("versions: %d", len(tables.Versions))
+	log.Printf("test emoji: %d", len(tables.Test))
+	log.Printf("sequences: %d", len(tables.Sequences))
+
+	out, err := format.Source(generate(*flagPackage, tables))
+	if err != nil {
+		log.Fatalf("could not format source: %v", err)
+	}
+	os.Stdout.Write(out)
+}
+
+// generate returns unformatted Go source declaring the passed tables.
+func generate(pkg string, tables *emoji.Tables) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by tablegen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import (\n\"unicode\"\n\n\"github.com/samthor/tr51\"\n\"github.com/samthor/tr51/emoji\"\n)\n\n")
+
+	properties := make([]tr51.Property, 0, len(tables.Properties))
+	for p := range tables.Properties {
+		properties = append(properties, p)
+	}
+	slices.Sort(properties)
+
+	for _, p := range properties {
+		fmt.Fprintf(&b, "// %s contains runes with the %s property.\n", identifier(p), p)
+		fmt.Fprintf(&b, "var %s = ", identifier(p))
+		writeRangeTable(&b, tables.Properties[p])
+		fmt.Fprintf(&b, "\n\n")
+	}
+
+	fmt.Fprintf(&b, "// Tables contains all generated TR51 data.\n")
+	fmt.Fprintf(&b, "var Tables = &emoji.Tables{\n")
+
+	fmt.Fprintf(&b, "Properties: map[tr51.Property]*unicode.RangeTable{\n")
+	for _, p := range properties {
+		fmt.Fprintf(&b, "%q: %s,\n", p, identifier(p))
+	}
+	fmt.Fprintf(&b, "},\n")
+
+	fmt.Fprintf(&b, "Versions: []emoji.VersionTable{\n")
+	for _, vt := range tables.Versions {
+		fmt.Fprintf(&b, "{Version: %s, Table: ", version(vt.Version))
+		writeRangeTable(&b, vt.Table)
+		fmt.Fprintf(&b, "},\n")
+	}
+	fmt.Fprintf(&b, "},\n")
+
+	fmt.Fprintf(&b, "Test: []emoji.TestLine{\n")
+	for _, line := range tables.Test {
+		fmt.Fprintf(&b, "{Emoji: %+q, Status: %q, Notes: %q, Group: %q, Subgroup: %q, Version: %s},\n",
+			line.Emoji, line.Status, line.Notes, line.Group, line.Subgroup, version(line.Version))
+	}
+	fmt.Fprintf(&b, "},\n")
+
+	fmt.Fprintf(&b, "TestCounts: emoji.TestCounts{\n")
+	writeCounts(&b, "Groups", "string", tables.TestCounts.Groups)
+	writeCounts(&b, "GroupsWithoutModifiers", "string", tables.TestCounts.GroupsWithoutModifiers)
+	writeCounts(&b, "Status", "tr51.Property", tables.TestCounts.Status)
+	fmt.Fprintf(&b, "},\n")
+
+	fmt.Fprintf(&b, "Sequences: []emoji.Sequence{\n")
+	for _, seq := range tables.Sequences {
+		fmt.Fprintf(&b, "{Emoji: %+q, Type: %q, Description: %q, Version: %s},\n",
+			seq.Emoji, seq.Type, seq.Description, version(seq.Version))
+	}
+	fmt.Fprintf(&b, "},\n")
+
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
+
+func writeRangeTable(b *bytes.Buffer, t *unicode.RangeTable) {
+	fmt.Fprintf(b, "&unicode.RangeTable{\n")
+	if len(t.R16) != 0 {
+		fmt.Fprintf(b, "R16: []unicode.Range16{\n")
+		for _, r := range t.R16 {
+			fmt.Fprintf(b, "{Lo: 0x%04x, Hi: 0x%04x, Stride: %d},\n", r.Lo, r.Hi, r.Stride)
+		}
+		fmt.Fprintf(b, "},\n")
+	}
+	if len(t.R32) != 0 {
+		fmt.Fprintf(b, "R32: []unicode.Range32{\n")
+		for _, r := range t.R32 {
+			fmt.Fprintf(b, "{Lo: 0x%x, Hi: 0x%x, Stride: %d},\n", r.Lo, r.Hi, r.Stride)
+		}
+		fmt.Fprintf(b, "},\n")
+	}
+	if t.LatinOffset != 0 {
+		fmt.Fprintf(b, "LatinOffset: %d,\n", t.LatinOffset)
+	}
+	fmt.Fprintf(b, "}")
+}
+
+// writeCounts writes a field of emoji.TestCounts, omitting it if nil.
+func writeCounts[K ~string](b *bytes.Buffer, field, keyType string, m map[K]int) {
+	if m == nil {
+		return
+	}
+	fmt.Fprintf(b, "%s: map[%s]int{\n", field, keyType)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		fmt.Fprintf(b, "%q: %d,\n", key, m[key])
+	}
+	fmt.Fprintf(b, "},\n")
+}
+
+func version(v tr51.Version) string {
+	return fmt.Sprintf("tr51.Version{Major: %d, Minor: %d}", v.Major, v.Minor)
+}
+
+// identifier returns an exported Go identifier for the passed property, e.g., "Emoji_Presentation"
+// becomes "EmojiPresentation".
+func identifier(p tr51.Property) string {
+	parts := strings.FieldsFunc(string(p), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// readTR51 returns a Reader for the passed file, or nil if it doesn't exist.
+func readTR51(filename string) *tr51.Reader {
+	all, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("skipping %v: not found", filename)
+		return nil
+	} else if err != nil {
+		log.Fatalf("could not read %v: %v", filename, err)
+	}
+	return tr51.NewReader(bytes.NewBuffer(all))
+}
@@ -0,0 +1,67 @@
+// Package main of testdiff prints the changes between two versions of emoji-test.txt.
+//
+// Usage:
+//
+//	testdiff [-json] old/emoji-test.txt new/emoji-test.txt
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/samthor/tr51"
+	"github.com/samthor/tr51/emoji"
+)
+
+var (
+	flagJSON = flag.Bool("json", false, "write changes as JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: testdiff [-json] old new\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	older := readTest(flag.Arg(0))
+	newer := readTest(flag.Arg(1))
+	d := older.Diff(newer)
+
+	if *flagJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := d.WriteText(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("added=%d removed=%d renamed=%d moved=%d status=%d",
+		len(d.Added), len(d.Removed), len(d.Renamed), len(d.Moved), len(d.Status))
+}
+
+func readTest(filename string) *emoji.Test {
+	all, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("could not read %v: %v", filename, err)
+	}
+
+	t, err := emoji.NewTest(tr51.NewReader(bytes.NewBuffer(all)))
+	if err != nil {
+		log.Fatalf("could not parse %v: %v", filename, err)
+	}
+	return t
+}
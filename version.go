@@ -0,0 +1,56 @@
+package tr51
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a Unicode or emoji version, e.g., 13.1.
+type Version struct {
+	Major, Minor int
+}
+
+// ParseVersion parses a version such as "13.1". A missing minor version is treated as zero.
+func ParseVersion(s string) (Version, error) {
+	major, minor, hasMinor := strings.Cut(s, ".")
+	var v Version
+	var err error
+	if v.Major, err = parseVersionPart(major); err != nil {
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	if hasMinor {
+		if v.Minor, err = parseVersionPart(minor); err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+	}
+	return v, nil
+}
+
+// String returns the version in the form "13.1".
+func (v Version) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is less than, equal to or greater than o.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major < o.Major:
+		return -1
+	case v.Major > o.Major:
+		return +1
+	case v.Minor < o.Minor:
+		return -1
+	case v.Minor > o.Minor:
+		return +1
+	}
+	return 0
+}
+
+// parseVersionPart parses a non-negative decimal number without sign or other prefix.
+func parseVersionPart(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.Atoi(s)
+}
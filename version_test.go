@@ -0,0 +1,50 @@
+package tr51
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	testdata := map[string]Version{
+		"13.1": Version{13, 1},
+		"0.6":  Version{0, 6},
+		"15":   Version{15, 0},
+		"1.10": Version{1, 10},
+	}
+	for input, expected := range testdata {
+		actual, err := ParseVersion(input)
+		if err != nil {
+			t.Errorf("got err for %s: %v", input, err)
+		}
+		if actual != expected {
+			t.Errorf("expected %v, was %v", expected, actual)
+		}
+		if actual.String() != input && input != "15" {
+			t.Errorf("expected String to return %s, was %s", input, actual.String())
+		}
+	}
+
+	for _, input := range []string{"", "1.", ".1", "1.1.1", "x.y", "-1.0", "+1.0"} {
+		if _, err := ParseVersion(input); !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("expected ErrInvalidVersion for %q, was %v", input, err)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []Version{{0, 6}, {1, 0}, {1, 1}, {1, 10}, {13, 0}, {13, 1}}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			var expected int
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = +1
+			}
+			if actual := a.Compare(b); actual != expected {
+				t.Errorf("expected %v.Compare(%v) to be %d, was %d", a, b, expected, actual)
+			}
+		}
+	}
+}
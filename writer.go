@@ -0,0 +1,281 @@
+package tr51
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	glyphsWidth = 8 // e.g., "(😀)       grinning face"
+	zwj         = 0x200d
+)
+
+// columns are where the code points and each field of a line end, matching the Unicode file that
+// the line is probably from. Fields past the end, or with a zero column, are not aligned.
+type columns struct {
+	points int
+	fields []int
+	parens bool // whether a lone Unicode version is in parentheses, e.g., "(1.1)"
+}
+
+var (
+	// e.g., "1F93C..1F93E  ; Emoji                # 9.0"
+	dataColumns = columns{points: 14, fields: []int{37}}
+	// e.g., "1F600 ...    ; fully-qualified     # 😀"
+	testColumns = columns{points: 55, fields: []int{77}}
+	// e.g., "0023 FE0F 20E3; RGI_Emoji_Keycap_Sequence    ; keycap: \x{23} ...    # E0.6"
+	sequenceColumns = columns{points: 14, fields: []int{45, 110}}
+	// e.g., "1F468 200D 1F4BB ...    ; RGI_Emoji_ZWJ_Sequence  ; man technologist ...    # E4.0"
+	zwjColumns = columns{points: 44, fields: []int{70, 135}}
+	// e.g., "0023 FE0E  ; text style;  # (1.1)"
+	variationColumns = columns{points: 11, fields: []int{0, 26}, parens: true}
+)
+
+// Writer writes TR51 data in the format read by Reader.
+type Writer struct {
+	w       *bufio.Writer
+	written bool
+}
+
+// NewWriter returns a new Writer for TR51 data.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes a single Line. Lines with emoji are aligned in the style of emoji-test.txt if they
+// have a single glyph, no count and notes which can't be misread as a version. Lines with two
+// fields are aligned like emoji-sequences.txt, emoji-zwj-sequences.txt or
+// emoji-variation-sequences.txt, and other lines like emoji-data.txt. Lines without emoji are
+// written as comments containing their Notes, which may be empty.
+//
+// The name fields are derived from Notes, so are not written separately.
+func (w *Writer) Write(l Line) error {
+	w.written = true
+	_, err := w.w.Write(appendLine(nil, l))
+	return err
+}
+
+// WriteComment writes a comment line.
+func (w *Writer) WriteComment(s string) error {
+	return w.Write(Line{Notes: s})
+}
+
+// WriteGroup writes a group header, separated from any previous line.
+func (w *Writer) WriteGroup(name string) error {
+	return w.writeSection("group: " + name)
+}
+
+// WriteSubgroup writes a subgroup header, separated from any previous line.
+func (w *Writer) WriteSubgroup(name string) error {
+	return w.writeSection("subgroup: " + name)
+}
+
+// WriteHeader writes the non-empty parts of the passed Header as leading comments.
+func (w *Writer) WriteHeader(h Header) error {
+	var version string
+	if h.Version != (Version{}) {
+		version = h.Version.String()
+	}
+	for _, s := range []string{h.File, prefixIfSet("Date: ", h.Date), h.Copyright, prefixIfSet("Version: ", version)} {
+		if s == "" {
+			continue
+		}
+		if err := w.WriteComment(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer) writeSection(s string) error {
+	if w.written {
+		if err := w.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return w.WriteComment(s)
+}
+
+// appendLine appends the canonical form of the passed Line, including a trailing newline.
+func appendLine(dst []byte, l Line) []byte {
+	if !l.HasEmoji() {
+		dst = append(dst, '#')
+		if l.Notes != "" {
+			dst = append(dst, ' ')
+			dst = appendEscaped(dst, l.Notes, false)
+		}
+		return append(dst, '\n')
+	}
+
+	// the style of emoji-test.txt has a single glyph followed by notes
+	testStyle := l.GlyphLow != "" && l.GlyphHigh == "" && l.Count == 0 && l.Notes != "" &&
+		isPlainNotes(l.GlyphLow, l.Notes)
+	cols := columnsOf(l, testStyle)
+
+	start := len(dst)
+	if l.Single != 0 {
+		dst = appendPoint(dst, l.Single)
+	} else if l.High != 0 {
+		dst = appendPoint(dst, l.Low)
+		dst = append(dst, rangeSep...)
+		dst = appendPoint(dst, l.High)
+	} else {
+		for i, r := range l.Sequence {
+			if i != 0 {
+				dst = append(dst, ' ')
+			}
+			dst = appendPoint(dst, r)
+		}
+	}
+
+	if len(l.Properties) != 0 {
+		dst = appendPadding(dst, start, cols.points)
+		for i, p := range l.Properties {
+			dst = append(dst, "; "...)
+			dst = appendEscaped(dst, p, true)
+			if i < len(cols.fields) {
+				dst = appendPadding(dst, start, cols.fields[i])
+			} else {
+				dst = append(dst, ' ')
+			}
+		}
+	} else {
+		dst = append(dst, ' ')
+	}
+
+	versions := formatVersions(l)
+	hasComment := versions != "" || l.Count != 0 || l.GlyphLow != "" || l.GlyphHigh != "" || l.Notes != ""
+	if !hasComment {
+		return append(bytes.TrimRight(dst, " "), '\n')
+	}
+	dst = append(dst, "# "...)
+
+	if testStyle {
+		dst = append(dst, l.GlyphLow...)
+		if versions != "" {
+			dst = append(dst, ' ')
+			dst = append(dst, versions...)
+		}
+	} else {
+		var prefix string
+		if versions != "" {
+			if cols.parens && l.UnicodeVersion != (Version{}) && l.EmojiVersion == (Version{}) && !l.Reserved {
+				prefix = "(" + versions + ") "
+			} else {
+				prefix = fmt.Sprintf("%4s ", versions)
+			}
+		}
+		if l.Count != 0 {
+			prefix += fmt.Sprintf("%5s ", "["+strconv.Itoa(l.Count)+"]")
+		}
+		dst = append(dst, prefix...)
+
+		// glyphs are optional, but are written if empty when notes would be read as them
+		if l.GlyphLow != "" || !isPlainNotes(strings.TrimSpace(prefix), l.Notes) {
+			glyphs := "(" + l.GlyphLow
+			if l.GlyphHigh != "" {
+				glyphs += string(rangeSep) + l.GlyphHigh
+			}
+			glyphs += ")"
+			dst = append(dst, glyphs...)
+			if l.Notes != "" {
+				dst = appendPadding(dst, len(dst), glyphsWidth-utf8.RuneCountInString(glyphs))
+			}
+		} else if l.Notes == "" {
+			dst = bytes.TrimRight(dst, " ")
+		} else {
+			dst = dst[:len(dst)-1] // a space is added before notes
+		}
+	}
+
+	if l.Notes != "" {
+		dst = append(dst, ' ')
+		dst = appendEscaped(dst, l.Notes, false)
+	}
+	return append(dst, '\n')
+}
+
+// columnsOf returns the columns to align the passed Line to.
+func columnsOf(l Line, testStyle bool) columns {
+	switch {
+	case testStyle:
+		return testColumns
+	case len(l.Properties) != 2:
+		return dataColumns
+	case Property(l.Properties[0]) == PropertyTextStyle || Property(l.Properties[0]) == PropertyEmojiStyle:
+		return variationColumns
+	case slices.Contains(l.Sequence, zwj):
+		return zwjColumns
+	}
+	return sequenceColumns
+}
+
+// isPlainNotes returns whether the passed notes are read back unchanged after the passed prefix of
+// a comment, such as a glyph in emoji-test.txt, rather than partly as a version, count or glyphs.
+func isPlainNotes(prefix, notes string) bool {
+	comment := notes
+	if prefix != "" {
+		comment = prefix + " " + notes
+	}
+	var l Line
+	return parseComment(comment, &l, false) == nil && l.Notes == notes
+}
+
+// appendPoint appends a hex code point, zero-padded to four digits.
+func appendPoint(dst []byte, r rune) []byte {
+	return append(dst, fmt.Sprintf("%04X", r)...)
+}
+
+// formatVersions returns the versions of the passed Line, e.g., "6.1", "E0.6" or "NA".
+func formatVersions(l Line) string {
+	var parts []string
+	if l.Reserved {
+		parts = append(parts, "NA")
+	}
+	if l.UnicodeVersion != (Version{}) {
+		parts = append(parts, l.UnicodeVersion.String())
+	}
+	if l.EmojiVersion != (Version{}) {
+		parts = append(parts, "E"+l.EmojiVersion.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// appendPadding appends spaces until the text since start is column runes wide. Like Unicode's
+// files, nothing is appended if it's already wider, as fields are separated by ';' or '#'.
+func appendPadding(dst []byte, start, column int) []byte {
+	for n := utf8.RuneCount(dst[start:]); n < column; n++ {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+// appendEscaped appends s, escaping bytes that would otherwise be parsed as TR51 syntax.
+func appendEscaped(dst []byte, s string, field bool) []byte {
+	for _, r := range s {
+		if r < ' ' || r == '\\' || (field && (r == '#' || r == ';')) {
+			dst = append(dst, fmt.Sprintf(`\x{%X}`, r)...)
+		} else {
+			dst = utf8.AppendRune(dst, r)
+		}
+	}
+	return dst
+}
+
+func prefixIfSet(prefix, s string) string {
+	if s == "" {
+		return ""
+	}
+	return prefix + s
+}
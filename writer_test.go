@@ -0,0 +1,174 @@
+package tr51
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	type testData struct {
+		line Line
+		out  string
+	}
+	data := []testData{
+		{
+			Line{Notes: "blah"},
+			"# blah\n",
+		},
+		{
+			Line{Single: 0x1f93c},
+			"1F93C\n",
+		},
+		{
+			Line{Low: 0x1f93c, High: 0x1f93e, UnicodeVersion: Version{9, 0}, Count: 3, GlyphLow: "🤼", GlyphHigh: "🤾", Notes: "people wrestling..person playing handball", Properties: []string{"Emoji"}},
+			"1F93C..1F93E  ; Emoji                #  9.0   [3] (🤼..🤾)   people wrestling..person playing handball\n",
+		},
+		{
+			Line{Single: 0x1f600, EmojiVersion: Version{1, 0}, GlyphLow: "😀", Notes: "grinning face", Properties: []string{"fully-qualified"}},
+			"1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face\n",
+		},
+		{
+			Line{Single: 0x1f61f, Notes: "blah"},
+			"1F61F # blah\n",
+		},
+		{
+			Line{Single: 0x1f61f, Notes: "(😀) blah"},
+			"1F61F # ()       (😀) blah\n",
+		},
+		{
+			Line{Sequence: []rune{0x0023, 0xfe0f, 0x20e3}, EmojiVersion: Version{0, 6}, Count: 1, GlyphLow: "#️⃣", Properties: []string{"RGI_Emoji_Keycap_Sequence", "keycap: #"}},
+			"0023 FE0F 20E3; RGI_Emoji_Keycap_Sequence    ; keycap: \\x{23}                                                 # E0.6   [1] (#️⃣)\n",
+		},
+		{
+			Line{Sequence: []rune{0x1f468, 0x200d, 0x1f4bb}, EmojiVersion: Version{4, 0}, Count: 1, GlyphLow: "👨‍💻", Properties: []string{"RGI_Emoji_ZWJ_Sequence", "man technologist"}},
+			"1F468 200D 1F4BB                            ; RGI_Emoji_ZWJ_Sequence  ; man technologist                                               # E4.0   [1] (👨‍💻)\n",
+		},
+		{
+			Line{Sequence: []rune{0x0023, 0xfe0e}, UnicodeVersion: Version{1, 1}, Notes: "NUMBER SIGN", Properties: []string{"text style", ""}},
+			"0023 FE0E  ; text style;  # (1.1) NUMBER SIGN\n",
+		},
+	}
+
+	for _, td := range data {
+		var b bytes.Buffer
+		w := NewWriter(&b)
+		if err := w.Write(td.line); err != nil {
+			t.Errorf("got err: %v", err)
+		}
+		w.Flush()
+		if actual := b.String(); actual != td.out {
+			t.Errorf("expected %q, was %q", td.out, actual)
+		}
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"# blah",
+		"1F61F # blah",
+		"1F61F ;	emoji ;	L1 ;	secondary ;	x	# V6.1 (😟) WORRIED FACE",
+		"2194..2199    ; Emoji                #   [6] (↔️..↙️)  LEFT RIGHT ARROW..SOUTH WEST ARROW",
+		"0023 FE0E  ; text style;  # (1.1) NUMBER SIGN",
+		"1F442..1F4F7  ; Emoji_Presentation   #  6.0[182] (👂..📷)    ear..camera",
+		`0023 FE0F 20E3; Emoji_Keycap_Sequence     ; keycap: \x{23}                                                 #  3.0  [1] (#️⃣)`,
+		`1F18E                                      ; fully-qualified     # 🆎 AB button (blood type)`,
+		`1F93F         ; Extended_Pictographic#   NA  [1] (🤿️)       <reserved-1F93F>`,
+		`1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face`,
+		`1F947                                                  ; fully-qualified     # 🥇 E3.0 1st place medal`,
+		`1F600 ; fully-qualified # 😀 E1.0 notes with \x{5C} backslash`,
+		`1F600 ; Emoji ; semi\x{3B}colon`,
+		"1F600 ; Emoji",
+		"1F600 ; Emoji # 😀",
+		"1F600 ; Emoji # 13.1 (😀)",
+		"263A ; Emoji # V1.1 E0.6 [1] (☺️) smiling face",
+		"263A ; fully-qualified # ☺️ V1.1 E0.6 smiling face",
+	}
+
+	for _, input := range inputs {
+		expected, err := Parse([]byte(input))
+		if err != nil {
+			t.Fatalf("got err: %v", err)
+		}
+
+		out := appendLine(nil, expected)
+		actual, err := Parse(bytes.TrimSuffix(out, []byte{'\n'}))
+		if err != nil {
+			t.Errorf("got err on %q: %v", out, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %+v, was %+v (via %q)", expected, actual, out)
+		}
+	}
+}
+
+func TestWriterNotes(t *testing.T) {
+	// notes which look like the start of a comment must be read back as notes
+	notes := []string{
+		"1.5 grinning",
+		"E1.0 grinning",
+		"[2] grinning",
+		"NA grinning",
+		"(1.1) grinning",
+		"😀 grinning",
+	}
+
+	for _, n := range notes {
+		for _, glyph := range []string{"", "😀"} {
+			line := Line{Single: 0x1f600, Properties: []string{"fully-qualified"}, GlyphLow: glyph, Notes: n}
+			out := appendLine(nil, line)
+			actual, err := Parse(bytes.TrimSuffix(out, []byte{'\n'}))
+			if err != nil {
+				t.Errorf("got err on %q: %v", out, err)
+				continue
+			}
+			if actual.Notes != n || actual.GlyphLow != glyph || actual.Count != 0 || actual.Reserved ||
+				actual.UnicodeVersion != (Version{}) || actual.EmojiVersion != (Version{}) {
+				t.Errorf("expected notes %q and glyph %q, was %+v (via %q)", n, glyph, actual, out)
+			}
+		}
+	}
+}
+
+func TestWriterSections(t *testing.T) {
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	w.WriteHeader(Header{File: "emoji-test.txt", Version: Version{15, 1}})
+	w.WriteGroup("Smileys & Emotion")
+	w.WriteSubgroup("face-smiling")
+	w.Write(Line{Single: 0x1f600, EmojiVersion: Version{1, 0}, GlyphLow: "😀", Notes: "grinning face", Properties: []string{"fully-qualified"}})
+	w.WriteSubgroup("face-affection")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("got err: %v", err)
+	}
+
+	r := NewReader(&b)
+	header, err := r.Header()
+	if err != nil {
+		t.Fatalf("got err: %v", err)
+	}
+	if expected := (Header{File: "emoji-test.txt", Version: Version{15, 1}}); header != expected {
+		t.Errorf("expected header %+v, was %+v", expected, header)
+	}
+
+	var notes []string
+	for {
+		l, err := r.Read()
+		if err != nil {
+			break
+		}
+		notes = append(notes, l.Notes)
+	}
+	expected := []string{
+		"emoji-test.txt",
+		"Version: 15.1",
+		"group: Smileys & Emotion",
+		"subgroup: face-smiling",
+		"grinning face",
+		"subgroup: face-affection",
+	}
+	if !reflect.DeepEqual(notes, expected) {
+		t.Errorf("expected notes %v, was %v", expected, notes)
+	}
+}